@@ -1,39 +1,18 @@
 package adapters
 
 import (
+	"errors"
+	"fmt"
 	"io"
-	"reflect"
 	"strconv"
 
 	"github.com/mirrorru/dbs"
-	"github.com/mirrorru/dot"
 )
 
 // DefaultFieldNameLength ожидаемая длина имени поля, используется для выделения памяти при создании запросов
 var DefaultFieldNameLength = 20
 
-type queryKind byte
-
-const (
-	queryKindInsertOne queryKind = iota
-	queryKindSelectOne
-	queryKindUpdateOne
-	queryKindDeleteOne
-)
-
-type queryCacheKey struct {
-	QueryOptions
-
-	Type reflect.Type
-	Kind queryKind
-}
-
-type QueryOptions struct {
-	WithTotals bool
-	WithAlias  string
-}
-
-var queryCache = dot.SyncStore[queryCacheKey, string]{}
+var ErrUnknownField = errors.New("unknown field")
 
 func WriteFieldInfoListNames(writer io.StringWriter, list dbs.FieldInfoList, sepaPrefix string) {
 	for idx := range list {
@@ -66,6 +45,19 @@ func WriteFieldInfoListEQs(writer io.StringWriter, list dbs.FieldInfoList, start
 	}
 }
 
+// peekFields - найти поля структуры по именам
+func peekFields(info *dbs.StructInfo, fieldNames []string) (dbs.FieldInfoList, error) {
+	result := make(dbs.FieldInfoList, 0, len(fieldNames))
+	for _, name := range fieldNames {
+		field, found := info.PeekField(name)
+		if !found {
+			return nil, fmt.Errorf("%w [%s] in [%s]", ErrUnknownField, name, info.TableName())
+		}
+		result = append(result, field)
+	}
+	return result, nil
+}
+
 func InsertOneArgs[T any](info *dbs.StructInfo, src *T) ([]any, error) {
 	return info.NonAutoFields().Refs(src)
 }
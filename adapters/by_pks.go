@@ -0,0 +1,196 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mirrorru/dbs"
+)
+
+// arrayParamer - диалект принимает массив параметром запроса: pk = ANY($1). Интерфейс не экспортируется:
+// форма запроса SelectByPKsQuery/DeleteByPKsQuery и параметры KeysArgs/PKsArgs должны определяться
+// одним и тем же диалектом, а обёртка над встроенным диалектом переопределила бы только параметры
+type arrayParamer interface {
+	arrayParams()
+}
+
+// canPassArrays - принимает ли диалект массив параметром запроса (см. arrayParamer)
+func canPassArrays(dialect Dialect) bool {
+	_, ok := dialect.(arrayParamer)
+	return ok
+}
+
+var (
+	errKeyValuesCount = errors.New("wrong count of PK values in key")
+	errKeyValueType   = errors.New("key value type doesn't match PK field")
+)
+
+// isArrayPK - список ключей передаётся одним параметром-массивом: pk = ANY($1)
+func isArrayPK(dialect Dialect, info *dbs.StructInfo) bool {
+	return canPassArrays(dialect) && len(info.PKFields()) == 1
+}
+
+// makeByPKsKey - ключ кэша запроса по списку PK. Для параметра-массива запрос не зависит от количества ключей
+func makeByPKsKey(dialect Dialect, info *dbs.StructInfo, kind queryKind, keyCount int) queryCacheKey {
+	key := makeCacheKey(info, kind)
+	if !isArrayPK(dialect, info) {
+		key.Rows = keyCount
+	}
+	return key
+}
+
+// writePKsCondition - условие "PK входит в список из keyCount ключей":
+// pk = ANY($1) для параметра-массива, pk IN (...) для простого PK, (a, b) IN ((...), ...) для составного,
+// а для диалектов без сравнения кортежей - ((a=@p1 AND b=@p2) OR ...)
+func writePKsCondition(sb *strings.Builder, dialect Dialect, info *dbs.StructInfo, keyCount int) {
+	pkFields := info.PKFields()
+	if isArrayPK(dialect, info) {
+		_, _ = sb.WriteString(dialect.Ident(pkFields[0].Name))
+		_, _ = sb.WriteString(" = ANY(")
+		_, _ = sb.WriteString(dialect.Placeholder(1))
+		_, _ = sb.WriteString(")")
+		return
+	}
+	if keyCount == 0 {
+		_, _ = sb.WriteString("1=0")
+		return
+	}
+	if !canCompareTuples(dialect) && len(pkFields) > 1 {
+		_, _ = sb.WriteString("(")
+		for idx := range keyCount {
+			if idx > 0 {
+				_, _ = sb.WriteString(" OR ")
+			}
+			_, _ = sb.WriteString("(")
+			writeEQs(sb, dialect, pkFields, idx*len(pkFields)+1, " AND ")
+			_, _ = sb.WriteString(")")
+		}
+		_, _ = sb.WriteString(")")
+		return
+	}
+
+	if len(pkFields) == 1 {
+		_, _ = sb.WriteString(dialect.Ident(pkFields[0].Name))
+		_, _ = sb.WriteString(" IN (")
+	} else {
+		_, _ = sb.WriteString("(")
+		writeNames(sb, dialect, pkFields, "")
+		_, _ = sb.WriteString(") IN (")
+	}
+	for idx := range keyCount {
+		if idx > 0 {
+			_, _ = sb.WriteString(", ")
+		}
+		if len(pkFields) == 1 {
+			_, _ = sb.WriteString(dialect.Placeholder(idx + 1))
+			continue
+		}
+		_, _ = sb.WriteString("(")
+		writePlaceholders(sb, dialect, pkFields, idx*len(pkFields)+1, ", ")
+		_, _ = sb.WriteString(")")
+	}
+	_, _ = sb.WriteString(")")
+}
+
+// buildSelectByPKs - выборка записей по списку из keyCount первичных ключей
+func buildSelectByPKs(dialect Dialect, info *dbs.StructInfo, keyCount int) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(30 + len(allFields)*DefaultFieldNameLength + keyCount*len(info.PKFields())*6)
+	_, _ = sb.WriteString("SELECT ")
+	writeNames(&sb, dialect, allFields, "")
+	_, _ = sb.WriteString(" FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" WHERE ")
+	writePKsCondition(&sb, dialect, info, keyCount)
+
+	return sb.String()
+}
+
+// buildDeleteByPKs - удаление записей по списку из keyCount первичных ключей
+func buildDeleteByPKs(dialect Dialect, info *dbs.StructInfo, keyCount int, withReturning bool) string {
+	var sb strings.Builder
+
+	sb.Grow(30 + len(info.AllFields())*DefaultFieldNameLength + keyCount*len(info.PKFields())*6)
+	_, _ = sb.WriteString("DELETE FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" WHERE ")
+	writePKsCondition(&sb, dialect, info, keyCount)
+	writeReturning(&sb, dialect, info.AllFields(), withReturning)
+
+	return sb.String()
+}
+
+// PKsArgs - параметры SelectByPKsQuery/DeleteByPKsQuery для записей src (запрос формируется для len(src) ключей)
+func PKsArgs[T any](dialect Dialect, info *dbs.StructInfo, src []T) ([]any, error) {
+	pkFields := info.PKFields()
+	keys := make([]any, len(src))
+	for idx := range src {
+		refs, err := pkFields.Refs(&src[idx])
+		if err != nil {
+			return nil, err
+		}
+		for refIdx := range refs {
+			refs[refIdx] = reflect.ValueOf(refs[refIdx]).Elem().Interface()
+		}
+		if len(refs) == 1 {
+			keys[idx] = refs[0]
+		} else {
+			keys[idx] = refs
+		}
+	}
+	return KeysArgs(dialect, info, keys...)
+}
+
+// KeysArgs - параметры SelectByPKsQuery/DeleteByPKsQuery для значений ключей keys
+// (запрос формируется для len(keys) ключей). Ключ простого PK - значение поля,
+// ключ составного PK - []any со значениями полей PK по порядку
+func KeysArgs(dialect Dialect, info *dbs.StructInfo, keys ...any) ([]any, error) {
+	pkFields := info.PKFields()
+	if isArrayPK(dialect, info) {
+		array := reflect.MakeSlice(reflect.SliceOf(pkFields[0].Type), len(keys), len(keys))
+		for idx := range keys {
+			value, err := keyValue(pkFields[0], keys[idx])
+			if err != nil {
+				return nil, err
+			}
+			array.Index(idx).Set(value)
+		}
+		return []any{pq.Array(array.Interface())}, nil
+	}
+
+	if len(pkFields) == 1 {
+		return keys, nil
+	}
+	args := make([]any, 0, len(keys)*len(pkFields))
+	for idx := range keys {
+		values, ok := keys[idx].([]any)
+		if !ok || len(values) != len(pkFields) {
+			return nil, fmt.Errorf("%w: %d values needed for [%s]", errKeyValuesCount, len(pkFields), info.TableName())
+		}
+		args = append(args, values...)
+	}
+	return args, nil
+}
+
+// keyValue - значение ключа, приведённое к типу поля PK для элемента параметра-массива
+func keyValue(field dbs.FieldInfo, key any) (reflect.Value, error) {
+	value := reflect.ValueOf(key)
+	switch {
+	case !value.IsValid():
+		return reflect.Value{}, fmt.Errorf("%w [%s]: nil", errKeyValueType, field.Name)
+	case value.Type() == field.Type:
+		return value, nil
+	case isNumberKind(value.Kind()) && isNumberKind(field.Type.Kind()):
+		return value.Convert(field.Type), nil
+	}
+	return reflect.Value{}, fmt.Errorf("%w [%s]: %T", errKeyValueType, field.Name, key)
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
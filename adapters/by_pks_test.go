@@ -0,0 +1,68 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByPKsQuery_SinglePK(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	pg := &adapters.PGAdapter{}
+	assert.Equal(t, "SELECT id, kind, name, aux_field FROM test_rec WHERE id = ANY($1)", pg.SelectByPKsQuery(si, 3))
+	assert.Equal(t, "DELETE FROM test_rec WHERE id = ANY($1) RETURNING id, kind, name, aux_field",
+		pg.DeleteByPKsQuery(si, 5))
+	assert.Equal(t, 2, pg.QueryCache().Len(), "array query doesn't depend on key count")
+
+	args, err := adapters.PKsArgs(pg, si, []TestRec{{TestKey: TestKey{ID: 1}}, {TestKey: TestKey{ID: 2}}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{pq.Array([]int64{1, 2})}, args)
+	args, err = adapters.KeysArgs(pg, si, 3, int64(4))
+	require.NoError(t, err)
+	assert.Equal(t, []any{pq.Array([]int64{3, 4})}, args)
+	_, err = adapters.KeysArgs(pg, si, "5")
+	require.Error(t, err)
+
+	mysql := &adapters.MySQLAdapter{}
+	assert.Equal(t, "SELECT id, kind, name, aux_field FROM test_rec WHERE id IN (?, ?)", mysql.SelectByPKsQuery(si, 2))
+	assert.Equal(t, "DELETE FROM test_rec WHERE 1=0", mysql.DeleteByPKsQuery(si, 0))
+	args, err = adapters.KeysArgs(mysql, si, int64(3), int64(4))
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(3), int64(4)}, args)
+}
+
+func TestByPKsQuery_CompositePK(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(PairKeyRec{})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"SELECT owner_id, item_id, amount FROM pair_key_rec WHERE (owner_id, item_id) IN (($1, $2), ($3, $4))",
+		(&adapters.PGAdapter{}).SelectByPKsQuery(si, 2))
+	assert.Equal(t,
+		"DELETE FROM pair_key_rec WHERE (owner_id, item_id) IN ((?1, ?2), (?3, ?4)) RETURNING owner_id, item_id, amount",
+		(&adapters.SQLiteAdapter{}).DeleteByPKsQuery(si, 2))
+	assert.Equal(t,
+		"DELETE FROM pair_key_rec OUTPUT deleted.owner_id, deleted.item_id, deleted.amount"+
+			" WHERE ((owner_id=@p1 AND item_id=@p2) OR (owner_id=@p3 AND item_id=@p4))",
+		(&adapters.MSSQLAdapter{}).DeleteByPKsQuery(si, 2))
+
+	pg := &adapters.PGAdapter{}
+	args, err := adapters.PKsArgs(pg, si, []PairKeyRec{{OwnerID: 1, ItemID: 2}, {OwnerID: 3, ItemID: 4}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(1), int64(2), int64(3), int64(4)}, args)
+	args, err = adapters.KeysArgs(pg, si, []any{int64(5), int64(6)})
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(5), int64(6)}, args)
+	_, err = adapters.KeysArgs(pg, si, int64(5))
+	require.Error(t, err)
+}
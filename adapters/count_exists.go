@@ -0,0 +1,116 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// CountMode - способ подсчёта записей в CountQuery
+type CountMode byte
+
+const (
+	// CountExact - точный подсчёт COUNT(*)
+	CountExact CountMode = iota
+	// CountApprox - оценка количества записей по статистике СУБД (pg_class.reltuples в PostgreSQL),
+	// без условий отбора. Для таблиц, статистика которых ещё не собиралась, возвращается 0
+	CountApprox
+)
+
+var (
+	errApproxCount      = errors.New("approximate count isn't supported by dialect")
+	errApproxCountWhere = errors.New("approximate count can't be filtered by criteria")
+)
+
+// ApproxCounter - необязательный интерфейс диалекта, умеющего оценивать количество записей по статистике
+// (CountApprox). Для диалектов без этого интерфейса CountApprox - ошибка
+type ApproxCounter interface {
+	// ApproxCountQuery - запрос оценки количества записей без параметров
+	ApproxCountQuery(info *dbs.StructInfo) string
+}
+
+// ExistsWrapper - необязательный интерфейс диалекта, не умеющего выбирать логическое значение EXISTS(...)
+// напрямую: подзапрос EXISTS оборачивается в prefix и suffix вместо SELECT EXISTS( и )
+type ExistsWrapper interface {
+	ExistsWrap() (prefix, suffix string)
+}
+
+// countQuery - общая часть CountQuery диалектов: запрос без условий кэшируется, условия дописываются к нему
+func countQuery(dialect Dialect, info *dbs.StructInfo, where *Criteria, mode CountMode) (string, []any, error) {
+	if mode == CountApprox {
+		counter, ok := dialect.(ApproxCounter)
+		switch {
+		case !ok:
+			return "", nil, fmt.Errorf("%w [%s]", errApproxCount, dialect.Name())
+		case !where.IsEmpty():
+			return "", nil, errApproxCountWhere
+		}
+		return counter.ApproxCountQuery(info), nil, nil
+	}
+
+	conds, args, err := criteriaConditions(dialect, info, where, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	query := dialect.QueryCache().getOrPut(makeCacheKey(info, queryKindCount), func() string {
+		var sb strings.Builder
+		_, _ = sb.WriteString("SELECT COUNT(*) FROM ")
+		writeTableName(&sb, dialect, info.TableName())
+		return sb.String()
+	})
+	if conds != "" {
+		query += " WHERE " + conds
+	}
+	return query, args, nil
+}
+
+// existsByPKQuery - общая часть ExistsByPKQuery диалектов
+func existsByPKQuery(dialect Dialect, info *dbs.StructInfo) string {
+	return dialect.QueryCache().getOrPut(makeCacheKey(info, queryKindExistsByPK), func() string {
+		var sb strings.Builder
+		sb.Grow(50 + len(info.PKFields())*2*DefaultFieldNameLength)
+		writeEQs(&sb, dialect, info.PKFields(), 1, " AND ")
+		return buildExists(dialect, info, sb.String())
+	})
+}
+
+// existsWhereQuery - общая часть ExistsWhereQuery диалектов: запрос без условий кэшируется
+func existsWhereQuery(dialect Dialect, info *dbs.StructInfo, where *Criteria) (string, []any, error) {
+	conds, args, err := criteriaConditions(dialect, info, where, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	if conds != "" {
+		return buildExists(dialect, info, conds), args, nil
+	}
+	return dialect.QueryCache().getOrPut(makeCacheKey(info, queryKindExistsWhere), func() string {
+		return buildExists(dialect, info, "")
+	}), nil, nil
+}
+
+// buildExists - выборка признака наличия записей, отобранных условиями conds (пустые - любая запись)
+func buildExists(dialect Dialect, info *dbs.StructInfo, conds string) string {
+	prefix, suffix := "SELECT EXISTS(", ")"
+	if wrapper, ok := dialect.(ExistsWrapper); ok {
+		prefix, suffix = wrapper.ExistsWrap()
+	}
+
+	var sb strings.Builder
+	sb.Grow(40 + len(prefix) + len(suffix) + len(conds) + len(info.TableName()))
+	_, _ = sb.WriteString(prefix)
+	_, _ = sb.WriteString("SELECT 1 FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	if conds != "" {
+		_, _ = sb.WriteString(" WHERE ")
+		_, _ = sb.WriteString(conds)
+	}
+	_, _ = sb.WriteString(suffix)
+	return sb.String()
+}
+
+// ExistsByPKArgs - параметры ExistsByPKQuery: значения PK записи src
+func ExistsByPKArgs[T any](info *dbs.StructInfo, src *T) ([]any, error) {
+	return info.PKFields().Refs(src)
+}
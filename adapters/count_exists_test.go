@@ -0,0 +1,79 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	pg := &adapters.PGAdapter{}
+	query, args, err := pg.CountQuery(si, nil, adapters.CountExact)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM test_rec", query)
+	assert.Empty(t, args)
+
+	query, args, err = pg.CountQuery(si, adapters.Where(si).Gt("kind", 2), adapters.CountExact)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM test_rec WHERE kind>$1", query)
+	assert.Equal(t, []any{2}, args)
+
+	query, _, err = pg.CountQuery(si, nil, adapters.CountApprox)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT GREATEST(reltuples, 0)::bigint FROM pg_class WHERE oid = 'test_rec'::regclass", query)
+	var counter adapters.ApproxCounter = pg
+	assert.Equal(t, query, counter.ApproxCountQuery(si))
+
+	schemaInfo, err := dbs.NewStructInfo(SchemaRec{})
+	require.NoError(t, err)
+	query, _, err = adapters.NewPGAdapter(adapters.QuoteAlways).CountQuery(schemaInfo, nil, adapters.CountApprox)
+	require.NoError(t, err)
+	assert.Contains(t, query, `oid = '"archive"."SchemaRec"'::regclass`)
+
+	_, _, err = pg.CountQuery(si, adapters.Where(si).Eq("id", 1), adapters.CountApprox)
+	require.Error(t, err)
+	_, _, err = (&adapters.MySQLAdapter{}).CountQuery(si, nil, adapters.CountApprox)
+	require.Error(t, err)
+	_, _, err = pg.CountQuery(si, adapters.Where(si).Eq("missing", 1), adapters.CountExact)
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+}
+
+func TestExistsQueries(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(PairKeyRec{})
+	require.NoError(t, err)
+
+	pg := &adapters.PGAdapter{}
+	assert.Equal(t, "SELECT EXISTS(SELECT 1 FROM pair_key_rec WHERE owner_id=$1 AND item_id=$2)", pg.ExistsByPKQuery(si))
+	assert.Equal(t,
+		"SELECT CASE WHEN EXISTS(SELECT 1 FROM pair_key_rec WHERE owner_id=@p1 AND item_id=@p2) THEN 1 ELSE 0 END",
+		(&adapters.MSSQLAdapter{}).ExistsByPKQuery(si))
+	var wrapper adapters.ExistsWrapper = &adapters.MSSQLAdapter{}
+	prefix, suffix := wrapper.ExistsWrap()
+	assert.Equal(t, "SELECT CASE WHEN EXISTS(", prefix)
+	assert.Equal(t, ") THEN 1 ELSE 0 END", suffix)
+
+	query, args, err := (&adapters.MySQLAdapter{}).ExistsWhereQuery(si, adapters.Where(si).Ge("amount", 10))
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT EXISTS(SELECT 1 FROM pair_key_rec WHERE amount>=?)", query)
+	assert.Equal(t, []any{10}, args)
+
+	query, args, err = pg.ExistsWhereQuery(si, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT EXISTS(SELECT 1 FROM pair_key_rec)", query)
+	assert.Empty(t, args)
+
+	rec := PairKeyRec{OwnerID: 1, ItemID: 2}
+	args, err = adapters.ExistsByPKArgs(si, &rec)
+	require.NoError(t, err)
+	assert.Equal(t, []any{&rec.OwnerID, &rec.ItemID}, args)
+}
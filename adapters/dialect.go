@@ -0,0 +1,114 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dot"
+)
+
+// Dialect - описание SQL-диалекта: формирование запросов для CRUD-операций,
+// параметры запросов, экранирование идентификаторов и возможности СУБД
+type Dialect interface {
+	// Name - имя диалекта, под которым он регистрируется в реестре
+	Name() string
+	// Placeholder - параметр запроса с порядковым номером idx (нумерация с 1)
+	Placeholder(idx int) string
+	// QuoteIdent - экранировать идентификатор (имя таблицы, поля, псевдонима)
+	QuoteIdent(name string) string
+	// Ident - идентификатор для подстановки в запрос, экранированный при необходимости согласно режиму диалекта
+	Ident(name string) string
+	// CanReturnValuesInDML - может ли СУБД возвращать значения полей из INSERT/UPDATE/DELETE
+	CanReturnValuesInDML() bool
+	// Paging - ограничение выборки limit строками (0 - без ограничения) с пропуском offset строк;
+	// ordered - есть ли в запросе ORDER BY
+	Paging(limit, offset int, ordered bool) string
+	// QueryCache - кэш запросов, сформированных этим экземпляром диалекта
+	QueryCache() *QueryCache
+	// LockClause - блокировка строк, дописываемая в конец SELECT; ошибка - диалект не поддерживает блокировку
+	LockClause(lock RowLock) (string, error)
+
+	InsertOneQuery(info *dbs.StructInfo) string
+	SelectOneQuery(info *dbs.StructInfo) string
+	// SelectOneLockedQuery - выборка записи по PK с блокировкой lock
+	SelectOneLockedQuery(info *dbs.StructInfo, lock RowLock) (string, error)
+	// SelectManyQuery - выборка записей с сортировкой и пагинацией из opts; ошибка - недопустимые опции
+	SelectManyQuery(info *dbs.StructInfo, opts QueryOptions) (string, error)
+	// SelectWithRefsQuery - выборка записей с присоединением таблиц их ссылочных полей (см. SelectWithRefsReceivers)
+	SelectWithRefsQuery(info *dbs.StructInfo) string
+	UpdateOneQuery(info *dbs.StructInfo) string
+	// UpdateFieldsQuery - обновление только полей fieldNames записи, найденной по PK
+	UpdateFieldsQuery(info *dbs.StructInfo, fieldNames ...string) (string, error)
+	DeleteOneQuery(info *dbs.StructInfo) string
+	// UpdateWhereQuery - обновление полей fieldNames у записей, отобранных where, и параметры условий
+	// (параметры запроса - UpdateWhereArgs). withReturning - вернуть AllFields обновлённых записей
+	UpdateWhereQuery(info *dbs.StructInfo, where *Criteria, withReturning bool, fieldNames ...string) (string, []any, error)
+	// DeleteWhereQuery - удаление записей, отобранных where, и параметры запроса.
+	// withReturning - вернуть AllFields удалённых записей
+	DeleteWhereQuery(info *dbs.StructInfo, where *Criteria, withReturning bool) (string, []any, error)
+	// SelectByPKsQuery - выборка записей по списку из keyCount первичных ключей (параметры - PKsArgs, KeysArgs)
+	SelectByPKsQuery(info *dbs.StructInfo, keyCount int) string
+	// DeleteByPKsQuery - удаление записей по списку из keyCount первичных ключей (параметры - PKsArgs, KeysArgs)
+	DeleteByPKsQuery(info *dbs.StructInfo, keyCount int) string
+
+	// CountQuery - количество записей, отобранных where (nil - всех записей), и параметры запроса
+	CountQuery(info *dbs.StructInfo, where *Criteria, mode CountMode) (string, []any, error)
+	// ExistsByPKQuery - признак наличия записи с заданным PK (параметры - ExistsByPKArgs)
+	ExistsByPKQuery(info *dbs.StructInfo) string
+	// ExistsWhereQuery - признак наличия записей, отобранных where, и параметры запроса
+	ExistsWhereQuery(info *dbs.StructInfo, where *Criteria) (string, []any, error)
+
+	// InsertManyQuery - многострочная вставка rowCount строк; ошибка - rowCount меньше 1
+	InsertManyQuery(info *dbs.StructInfo, rowCount int) (string, error)
+	// InsertManyBatchSize - наибольшее количество строк многострочной вставки, укладывающееся в пределы драйвера
+	InsertManyBatchSize(info *dbs.StructInfo) int
+}
+
+var (
+	dialectRegistry = dot.SyncStore[string, Dialect]{}
+
+	ErrUnknownDialect = errors.New("unknown dialect")
+)
+
+//nolint:gochecknoinits
+func init() {
+	RegisterDialect(&PGAdapter{}, "postgresql", "pg", "pgx")
+	RegisterDialect(&MySQLAdapter{}, "mariadb")
+	RegisterDialect(&SQLiteAdapter{}, "sqlite3")
+	RegisterDialect(&MSSQLAdapter{}, "mssql")
+}
+
+// RegisterDialect - зарегистрировать диалект под его именем и дополнительными псевдонимами.
+// Повторная регистрация под тем же именем заменяет ранее зарегистрированный диалект
+func RegisterDialect(dialect Dialect, aliases ...string) {
+	dialectRegistry.Put(normalizeDialectName(dialect.Name()), dialect)
+	for _, alias := range aliases {
+		dialectRegistry.Put(normalizeDialectName(alias), dialect)
+	}
+}
+
+// LookupDialect - найти зарегистрированный диалект по имени или псевдониму (без учёта регистра)
+func LookupDialect(name string) (Dialect, error) {
+	dialect, found := dialectRegistry.GetCurrent(normalizeDialectName(name))
+	if !found {
+		return nil, fmt.Errorf("%w [%s]", ErrUnknownDialect, name)
+	}
+	return dialect, nil
+}
+
+// DialectNames - отсортированный список имён и псевдонимов зарегистрированных диалектов
+func DialectNames() []string {
+	names := make([]string, 0, 8)
+	dialectRegistry.ForEach(func(name string, _ Dialect) {
+		names = append(names, name)
+	})
+	slices.Sort(names)
+	return names
+}
+
+func normalizeDialectName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,35 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupDialect(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"postgres", "PostgreSQL", " pgx "} {
+		dialect, err := adapters.LookupDialect(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, "postgres", dialect.Name(), name)
+	}
+
+	_, err := adapters.LookupDialect("unknown-engine")
+	require.ErrorIs(t, err, adapters.ErrUnknownDialect)
+
+	assert.Contains(t, adapters.DialectNames(), "postgres")
+}
+
+func TestPGAdapter_Syntax(t *testing.T) {
+	t.Parallel()
+
+	var dialect adapters.Dialect = &adapters.PGAdapter{}
+	assert.Equal(t, "$1", dialect.Placeholder(1))
+	assert.Equal(t, "$12", dialect.Placeholder(12))
+	assert.Equal(t, `"Order"`, dialect.QuoteIdent("Order"))
+	assert.Equal(t, `"a""b"`, dialect.QuoteIdent(`a"b`))
+	assert.True(t, dialect.CanReturnValuesInDML())
+}
@@ -0,0 +1,195 @@
+package adapters_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeResponse - ответ фейкового драйвера на очередной запрос
+type fakeResponse struct {
+	Columns      []string
+	Rows         [][]driver.Value
+	LastInsertID int64
+	RowsAffected int64
+	Err          error
+}
+
+// fakeCall - запрос, полученный фейковым драйвером
+type fakeCall struct {
+	Query string
+	Args  []any
+}
+
+// fakeConn - соединение, отвечающее заранее заданными ответами по порядку поступления запросов
+type fakeConn struct {
+	mx        sync.Mutex
+	responses []fakeResponse
+	calls     []fakeCall
+	txLog     []string
+
+	rollbackErr error // Ошибка, возвращаемая откатом транзакции
+}
+
+const fakeDriverName = "dbs-fake"
+
+var (
+	fakeConns   sync.Map // dsn -> *fakeConn
+	fakeRegOnce sync.Once
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	conn, ok := fakeConns.Load(dsn)
+	if !ok {
+		return nil, errors.New("unknown fake dsn")
+	}
+	return conn.(*fakeConn), nil
+}
+
+// openFakeDB - открыть БД, отвечающую на запросы ответами responses
+func openFakeDB(t *testing.T, responses ...fakeResponse) (*sql.DB, *fakeConn) {
+	t.Helper()
+	fakeRegOnce.Do(func() { sql.Register(fakeDriverName, fakeDriver{}) })
+
+	conn := &fakeConn{responses: responses}
+	fakeConns.Store(t.Name(), conn)
+	db, err := sql.Open(fakeDriverName, t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = db.Close()
+		fakeConns.Delete(t.Name())
+	})
+	return db, conn
+}
+
+func (c *fakeConn) Calls() []fakeCall {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return append([]fakeCall(nil), c.calls...)
+}
+
+func (c *fakeConn) TxLog() []string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return append([]string(nil), c.txLog...)
+}
+
+func (c *fakeConn) next(query string, args []driver.NamedValue) (fakeResponse, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	call := fakeCall{Query: query, Args: make([]any, len(args))}
+	for idx := range args {
+		call.Args[idx] = args[idx].Value
+	}
+	c.calls = append(c.calls, call)
+	if len(c.responses) == 0 {
+		return fakeResponse{}, errors.New("unexpected query: " + query)
+	}
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, resp.Err
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.txLog = append(c.txLog, "BEGIN")
+	return fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	resp, err := c.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return fakeResult{lastInsertID: resp.LastInsertID, rowsAffected: resp.RowsAffected}, nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	resp, err := c.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: resp.Columns, rows: resp.Rows}, nil
+}
+
+// CheckNamedValue - пропускаем аргументы как есть, без преобразования к driver.Value
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx fakeTx) Commit() error {
+	tx.conn.mx.Lock()
+	defer tx.conn.mx.Unlock()
+	tx.conn.txLog = append(tx.conn.txLog, "COMMIT")
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.conn.mx.Lock()
+	defer tx.conn.mx.Unlock()
+	tx.conn.txLog = append(tx.conn.txLog, "ROLLBACK")
+	return tx.conn.rollbackErr
+}
+
+type fakeResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
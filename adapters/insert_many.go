@@ -0,0 +1,143 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// Пределы количества параметров в одном запросе для драйверов поддерживаемых СУБД
+const (
+	pgMaxParams     = 65535
+	mysqlMaxParams  = 65535
+	sqliteMaxParams = 32766 // SQLITE_MAX_VARIABLE_NUMBER начиная с SQLite 3.32
+	mssqlMaxParams  = 2100
+	mssqlMaxRows    = 1000 // Предел строк в конструкторе VALUES
+)
+
+// insertManyCachedRows - многострочные вставки до этого количества строк кэшируются всегда, более длинные -
+// только полной порцией InsertManyBatchSize: иначе произвольные размеры порций неограниченно наполняли бы кэш
+const insertManyCachedRows = 64
+
+var (
+	errInsertManyRowsMismatch = errors.New("returned rows count mismatch")
+	errInsertManyNoRows       = errors.New("multi-row insert needs at least one row")
+)
+
+// insertManyQuery - общая часть InsertManyQuery диалектов: проверка количества строк и кэширование build
+func insertManyQuery(dialect Dialect, info *dbs.StructInfo, rowCount int, build func() string) (string, error) {
+	if rowCount < 1 {
+		return "", errInsertManyNoRows
+	}
+	if rowCount > insertManyCachedRows && rowCount != dialect.InsertManyBatchSize(info) {
+		return build(), nil
+	}
+	key := makeCacheKey(info, queryKindInsertMany)
+	key.Rows = rowCount
+	return dialect.QueryCache().getOrPut(key, build), nil
+}
+
+// batchSizeByParams - количество строк многострочной вставки, укладывающееся в предел параметров
+func batchSizeByParams(info *dbs.StructInfo, maxParams, maxRows int) int {
+	size := maxParams
+	if fieldsCount := len(info.NonAutoFields()); fieldsCount > 0 {
+		size = maxParams / fieldsCount
+	}
+	if maxRows > 0 && size > maxRows {
+		size = maxRows
+	}
+	return max(size, 1)
+}
+
+// InsertManyBatches - разбить src на порции, каждая из которых укладывается в предел параметров диалекта.
+// Порции - подслайсы src, поэтому значения, прочитанные в них из RETURNING, попадают в src
+func InsertManyBatches[T any](dialect Dialect, info *dbs.StructInfo, src []T) iter.Seq[[]T] {
+	return slices.Chunk(src, dialect.InsertManyBatchSize(info))
+}
+
+// InsertManyArgs - параметры многострочной вставки src, в порядке строк
+func InsertManyArgs[T any](info *dbs.StructInfo, src []T) ([]any, error) {
+	nonAutoFields := info.NonAutoFields()
+	result := make([]any, 0, len(src)*len(nonAutoFields))
+	for idx := range src {
+		args, err := nonAutoFields.Refs(&src[idx])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, args...)
+	}
+	return result, nil
+}
+
+// InsertManyReceivers - приёмники значений, возвращаемых многострочной вставкой, по одному набору на строку
+func InsertManyReceivers[T any](info *dbs.StructInfo, dest []T) ([][]any, error) {
+	result := make([][]any, len(dest))
+	for idx := range dest {
+		receivers, err := InsertOneReceivers(info, &dest[idx])
+		if err != nil {
+			return nil, err
+		}
+		result[idx] = receivers
+	}
+	return result, nil
+}
+
+// ScanInsertManyRows - прочитать строки, возвращённые многострочной вставкой, в соответствующие элементы dest.
+// Строки сопоставляются элементам по порядку, количество строк должно совпадать с len(dest).
+// Для SQL Server порядок строк OUTPUT не гарантирован, и функция неприменима (см. MSSQLAdapter.InsertManyQuery)
+func ScanInsertManyRows[T any](rows Rows, info *dbs.StructInfo, dest []T) error {
+	receivers, err := InsertManyReceivers(info, dest)
+	if err != nil {
+		return err
+	}
+	var count int
+	for rows.Next() {
+		if count >= len(dest) {
+			return fmt.Errorf("%w: more than %d", errInsertManyRowsMismatch, len(dest))
+		}
+		if err = rows.Scan(receivers[count]...); err != nil {
+			return err
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if count != len(dest) {
+		return fmt.Errorf("%w: got %d, expected %d", errInsertManyRowsMismatch, count, len(dest))
+	}
+	return nil
+}
+
+func writeInsertManyValues(sb *strings.Builder, dialect Dialect, fields dbs.FieldInfoList, rowCount int) {
+	_, _ = sb.WriteString(" VALUES ")
+	for row := range rowCount {
+		if row > 0 {
+			_, _ = sb.WriteString(", ")
+		}
+		_, _ = sb.WriteString("(")
+		writePlaceholders(sb, dialect, fields, row*len(fields)+1, ", ")
+		_, _ = sb.WriteString(")")
+	}
+}
+
+func buildInsertMany(dialect Dialect, info *dbs.StructInfo, rowCount int, withReturning bool) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	nonAutoFields := info.NonAutoFields()
+	sb.Grow(50 + len(allFields)*2*DefaultFieldNameLength + rowCount*len(nonAutoFields)*8)
+	_, _ = sb.WriteString("INSERT INTO ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" (")
+	writeNames(&sb, dialect, nonAutoFields, "")
+	_, _ = sb.WriteString(")")
+	writeInsertManyValues(&sb, dialect, nonAutoFields, rowCount)
+	writeReturning(&sb, dialect, allFields, withReturning)
+
+	return sb.String()
+}
@@ -0,0 +1,122 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertManyQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	query, err := (&adapters.PGAdapter{}).InsertManyQuery(si, 2)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"INSERT INTO test_rec (kind, name, aux_field) VALUES ($1, $2, $3), ($4, $5, $6)"+
+			" RETURNING id, kind, name, aux_field", query)
+	query, err = (&adapters.MySQLAdapter{}).InsertManyQuery(si, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO test_rec (kind, name, aux_field) VALUES (?, ?, ?), (?, ?, ?)", query)
+	query, err = (&adapters.MSSQLAdapter{}).InsertManyQuery(si, 1)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"INSERT INTO test_rec (kind, name, aux_field)"+
+			" OUTPUT inserted.id, inserted.kind, inserted.name, inserted.aux_field VALUES (@p1, @p2, @p3)", query)
+
+	_, err = (&adapters.PGAdapter{}).InsertManyQuery(si, 0)
+	require.Error(t, err)
+}
+
+func TestInsertManyQuery_CacheBound(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+	dialect := &adapters.MSSQLAdapter{}
+
+	for rowCount := 1; rowCount <= dialect.InsertManyBatchSize(si); rowCount += 50 {
+		_, err = dialect.InsertManyQuery(si, rowCount)
+		require.NoError(t, err)
+	}
+	_, err = dialect.InsertManyQuery(si, dialect.InsertManyBatchSize(si))
+	require.NoError(t, err)
+	assert.Equal(t, 3, dialect.QueryCache().Len(), "only short inserts and the full batch are cached")
+}
+
+func TestInsertManyBatches(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	pg := &adapters.PGAdapter{}
+	assert.Equal(t, 65535/3, pg.InsertManyBatchSize(si))
+	assert.Equal(t, 2100/3, (&adapters.MSSQLAdapter{}).InsertManyBatchSize(si))
+
+	recs := make([]TestRec, 50000)
+	var sizes []int
+	for batch := range adapters.InsertManyBatches(pg, si, recs) {
+		sizes = append(sizes, len(batch))
+	}
+	assert.Equal(t, []int{21845, 21845, 6310}, sizes)
+}
+
+func TestInsertManyArgsAndScan(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	recs := []TestRec{
+		{TestBody: TestBody{Kind: 1, Name: "one"}},
+		{TestBody: TestBody{Kind: 2, Name: "two"}},
+	}
+	args, err := adapters.InsertManyArgs(si, recs)
+	require.NoError(t, err)
+	assert.Equal(t, append(testRecNonPKRefs(&recs[0]), testRecNonPKRefs(&recs[1])...), args)
+
+	stamp := time.Date(2025, 5, 6, 7, 8, 9, 0, time.UTC)
+	db, _ := openFakeDB(t, fakeResponse{
+		Columns: []string{"id", "kind", "name", "aux_field"},
+		Rows: [][]driver.Value{
+			{int64(10), int64(1), "one", stamp},
+			{int64(11), int64(2), "two", stamp},
+		},
+	})
+	query, err := (&adapters.PGAdapter{}).InsertManyQuery(si, len(recs))
+	require.NoError(t, err)
+	rows, err := db.QueryContext(t.Context(), query, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	require.NoError(t, adapters.ScanInsertManyRows(rows, si, recs))
+	assert.Equal(t, int64(10), recs[0].ID)
+	assert.Equal(t, int64(11), recs[1].ID)
+	assert.Equal(t, stamp, recs[1].AuxField)
+}
+
+func TestScanInsertManyRows_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	db, _ := openFakeDB(t, fakeResponse{
+		Columns: []string{"id", "kind", "name", "aux_field"},
+		Rows:    [][]driver.Value{{int64(10), int64(1), "one", time.Now()}},
+	})
+	rows, err := db.QueryContext(t.Context(), "INSERT")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	recs := make([]TestRec, 2)
+	require.Error(t, adapters.ScanInsertManyRows(rows, si, recs))
+}
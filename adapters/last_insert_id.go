@@ -0,0 +1,89 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mirrorru/dbs"
+)
+
+// SQLQuerier - общий интерфейс *sql.DB, *sql.Tx и *sql.Conn
+type SQLQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+var (
+	errMultipleAutoPK   = errors.New("LastInsertId can fill only single auto PK field")
+	errAutoPKNotInteger = errors.New("auto PK field must be integer to receive LastInsertId")
+)
+
+// InsertOneByLastInsertID - вставка записи для диалектов, не умеющих возвращать значения из DML.
+// Автогенерируемое поле первичного ключа заполняется из sql.Result.LastInsertId,
+// остальные автогенерируемые поля дочитываются отдельным запросом по первичному ключу
+func InsertOneByLastInsertID[T any](
+	ctx context.Context, db SQLQuerier, dialect Dialect, info *dbs.StructInfo, src *T,
+) error {
+	args, err := InsertOneArgs(info, src)
+	if err != nil {
+		return err
+	}
+	result, err := db.ExecContext(ctx, dialect.InsertOneQuery(info), args...)
+	if err != nil {
+		return err
+	}
+
+	autoFields := info.AutoFields()
+	autoPKFields := autoFields.Filter(func(fi dbs.FieldInfo) bool { return fi.IsPK })
+	switch len(autoPKFields) {
+	case 0:
+	case 1:
+		if err = setLastInsertID(result, autoPKFields, src); err != nil {
+			return err
+		}
+	default:
+		return errMultipleAutoPK
+	}
+	if len(autoPKFields) == len(autoFields) {
+		return nil
+	}
+
+	if args, err = SelectOneArgs(info, src); err != nil {
+		return err
+	}
+	receivers, err := autoFields.Refs(src)
+	if err != nil {
+		return err
+	}
+	query := dialect.QueryCache().getOrPut(makeCacheKey(info, queryKindSelectAuto), func() string {
+		return buildSelectAuto(dialect, info)
+	})
+
+	return db.QueryRowContext(ctx, query, args...).Scan(receivers...)
+}
+
+func setLastInsertID(result sql.Result, autoPKFields dbs.FieldInfoList, dest any) error {
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	refs, err := autoPKFields.Refs(dest)
+	if err != nil {
+		return err
+	}
+
+	fld := reflect.ValueOf(refs[0]).Elem()
+	switch fld.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fld.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fld.SetUint(uint64(id)) //nolint:gosec
+	default:
+		return fmt.Errorf("%w [%s]", errAutoPKNotInteger, autoPKFields[0].Name)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package adapters
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// MSSQLAdapter - диалект Microsoft SQL Server.
+// Вместо RETURNING используется OUTPUT inserted.*/deleted.*, вместо LIMIT - TOP и OFFSET/FETCH.
+// Настройки задаются конструктором NewMSSQLAdapter и не меняются, методы объявлены у указателя (см. PGAdapter)
+type MSSQLAdapter struct {
+	cache QueryCache
+
+	quoting QuoteMode // Режим экранирования идентификаторов
+}
+
+var _ Dialect = (*MSSQLAdapter)(nil)
+
+// NewMSSQLAdapter - диалект SQL Server с режимом экранирования идентификаторов quoting
+func NewMSSQLAdapter(quoting QuoteMode) *MSSQLAdapter {
+	return &MSSQLAdapter{quoting: quoting}
+}
+
+const (
+	mssqlInserted = "inserted"
+	mssqlDeleted  = "deleted"
+)
+
+func (*MSSQLAdapter) Name() string {
+	return "sqlserver"
+}
+
+func (*MSSQLAdapter) Placeholder(idx int) string {
+	return "@p" + strconv.Itoa(idx)
+}
+
+func (*MSSQLAdapter) QuoteIdent(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+func (a *MSSQLAdapter) Ident(name string) string {
+	return identByMode(a, a.quoting, plainIdentRe, mssqlReservedWords, name)
+}
+
+func (*MSSQLAdapter) CanReturnValuesInDML() bool {
+	return true
+}
+
+func (a *MSSQLAdapter) QueryCache() *QueryCache {
+	return &a.cache
+}
+
+// Paging - OFFSET/FETCH допустимы только после ORDER BY, при отсутствии сортировки добавляется фиктивная
+func (*MSSQLAdapter) Paging(limit, offset int, ordered bool) string {
+	var sb strings.Builder
+	if !ordered {
+		_, _ = sb.WriteString(" ORDER BY (SELECT NULL)")
+	}
+	_, _ = sb.WriteString(" OFFSET ")
+	_, _ = sb.WriteString(strconv.Itoa(offset))
+	_, _ = sb.WriteString(" ROWS")
+	if limit > 0 {
+		_, _ = sb.WriteString(" FETCH NEXT ")
+		_, _ = sb.WriteString(strconv.Itoa(limit))
+		_, _ = sb.WriteString(" ROWS ONLY")
+	}
+	return sb.String()
+}
+
+// LockClause - SQL Server блокирует строки табличными подсказками (UPDLOCK, READPAST), а не FOR UPDATE
+func (a *MSSQLAdapter) LockClause(lock RowLock) (string, error) {
+	return noLockClause(a, lock)
+}
+
+func (*MSSQLAdapter) CanCompareTuples() bool {
+	return false
+}
+
+func (a *MSSQLAdapter) InsertOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindInsertOne), func() string {
+		var sb strings.Builder
+
+		allFields := info.AllFields()
+		sb.Grow(50 + len(allFields)*4*DefaultFieldNameLength)
+		_, _ = sb.WriteString("INSERT INTO ")
+		writeTableName(&sb, a, info.TableName())
+		_, _ = sb.WriteString(" (")
+		writeNames(&sb, a, info.NonAutoFields(), "")
+		_, _ = sb.WriteString(")")
+		writeMSSQLOutput(&sb, a, mssqlInserted, allFields)
+		_, _ = sb.WriteString(" VALUES (")
+		writePlaceholders(&sb, a, info.NonAutoFields(), 1, ", ")
+		_, _ = sb.WriteString(")")
+
+		return sb.String()
+	})
+}
+
+func (a *MSSQLAdapter) SelectOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectOne), func() string {
+		var sb strings.Builder
+
+		allFields := info.AllFields()
+		pkFields := info.PKFields()
+		sb.Grow(30 + len(allFields)*2*DefaultFieldNameLength + len(pkFields)*DefaultFieldNameLength)
+
+		_, _ = sb.WriteString("SELECT TOP (1) ")
+		writeNames(&sb, a, allFields, "")
+		_, _ = sb.WriteString(" FROM ")
+		writeTableName(&sb, a, info.TableName())
+		_, _ = sb.WriteString(" WHERE ")
+		writeEQs(&sb, a, pkFields, 1, " AND ")
+		_, _ = sb.WriteString(";")
+
+		return sb.String()
+	})
+}
+
+func (a *MSSQLAdapter) SelectOneLockedQuery(info *dbs.StructInfo, lock RowLock) (string, error) {
+	return selectOneLockedQuery(a, info, lock)
+}
+
+func (a *MSSQLAdapter) SelectManyQuery(info *dbs.StructInfo, opts QueryOptions) (string, error) {
+	return selectManyQuery(a, &a.cache, info, opts, false)
+}
+
+func (a *MSSQLAdapter) SelectWithRefsQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectWithRefs), func() string {
+		return buildSelectWithRefs(a, info)
+	})
+}
+
+func (a *MSSQLAdapter) UpdateOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindUpdateOne), func() string {
+		return a.buildUpdateFields(info, info.NonPKFields())
+	})
+}
+
+func (a *MSSQLAdapter) UpdateFieldsQuery(info *dbs.StructInfo, fieldNames ...string) (string, error) {
+	setFields, err := updateFieldsList(info, fieldNames)
+	if err != nil {
+		return "", err
+	}
+	return a.cache.getOrPut(makeUpdateFieldsKey(info, fieldNames), func() string {
+		return a.buildUpdateFields(info, setFields)
+	}), nil
+}
+
+func (a *MSSQLAdapter) buildUpdateFields(info *dbs.StructInfo, setFields dbs.FieldInfoList) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(40 + (2*len(allFields)+len(setFields))*2*DefaultFieldNameLength)
+	_, _ = sb.WriteString("UPDATE ")
+	writeTableName(&sb, a, info.TableName())
+	_, _ = sb.WriteString(" SET ")
+	writeEQs(&sb, a, setFields, 1, ", ")
+	writeMSSQLOutput(&sb, a, mssqlInserted, allFields)
+	_, _ = sb.WriteString(" WHERE ")
+	writeEQs(&sb, a, info.PKFields(), len(setFields)+1, " AND ")
+
+	return sb.String()
+}
+
+func (a *MSSQLAdapter) DeleteOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindDeleteOne), func() string {
+		var sb strings.Builder
+
+		allFields := info.AllFields()
+		pkFields := info.PKFields()
+		sb.Grow(20 + len(allFields)*2*DefaultFieldNameLength)
+		_, _ = sb.WriteString("DELETE FROM ")
+		writeTableName(&sb, a, info.TableName())
+		writeMSSQLOutput(&sb, a, mssqlDeleted, allFields)
+		_, _ = sb.WriteString(" WHERE ")
+		writeEQs(&sb, a, pkFields, 1, " AND ")
+
+		return sb.String()
+	})
+}
+
+func (a *MSSQLAdapter) SelectByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindSelectByPKs, keyCount), func() string {
+		return buildSelectByPKs(a, info, keyCount)
+	})
+}
+
+func (a *MSSQLAdapter) DeleteByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindDeleteByPKs, keyCount), func() string {
+		var sb strings.Builder
+
+		allFields := info.AllFields()
+		sb.Grow(30 + len(allFields)*2*DefaultFieldNameLength + keyCount*len(info.PKFields())*16)
+		_, _ = sb.WriteString("DELETE FROM ")
+		writeTableName(&sb, a, info.TableName())
+		writeMSSQLOutput(&sb, a, mssqlDeleted, allFields)
+		_, _ = sb.WriteString(" WHERE ")
+		writePKsCondition(&sb, a, info, keyCount)
+
+		return sb.String()
+	})
+}
+
+func (a *MSSQLAdapter) UpdateWhereQuery(
+	info *dbs.StructInfo, where *Criteria, withReturning bool, fieldNames ...string,
+) (string, []any, error) {
+	setFields, conds, args, err := prepareUpdateWhere(a, info, where, withReturning, fieldNames)
+	if err != nil {
+		return "", nil, err
+	}
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(30 + (len(allFields)+len(setFields))*2*DefaultFieldNameLength + len(conds))
+	_, _ = sb.WriteString("UPDATE ")
+	writeTableName(&sb, a, info.TableName())
+	_, _ = sb.WriteString(" SET ")
+	writeEQs(&sb, a, setFields, 1, ", ")
+	if withReturning {
+		writeMSSQLOutput(&sb, a, mssqlInserted, allFields)
+	}
+	_, _ = sb.WriteString(" WHERE ")
+	_, _ = sb.WriteString(conds)
+
+	return sb.String(), args, nil
+}
+
+func (a *MSSQLAdapter) DeleteWhereQuery(info *dbs.StructInfo, where *Criteria, withReturning bool) (string, []any, error) {
+	conds, args, err := whereConditions(a, info, where, 0, withReturning)
+	if err != nil {
+		return "", nil, err
+	}
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(30 + len(allFields)*2*DefaultFieldNameLength + len(conds))
+	_, _ = sb.WriteString("DELETE FROM ")
+	writeTableName(&sb, a, info.TableName())
+	if withReturning {
+		writeMSSQLOutput(&sb, a, mssqlDeleted, allFields)
+	}
+	_, _ = sb.WriteString(" WHERE ")
+	_, _ = sb.WriteString(conds)
+
+	return sb.String(), args, nil
+}
+
+func (a *MSSQLAdapter) CountQuery(info *dbs.StructInfo, where *Criteria, mode CountMode) (string, []any, error) {
+	return countQuery(a, info, where, mode)
+}
+
+func (a *MSSQLAdapter) ExistsByPKQuery(info *dbs.StructInfo) string {
+	return existsByPKQuery(a, info)
+}
+
+func (a *MSSQLAdapter) ExistsWhereQuery(info *dbs.StructInfo, where *Criteria) (string, []any, error) {
+	return existsWhereQuery(a, info, where)
+}
+
+// SavepointQueries - точки сохранения SQL Server не освобождаются и существуют до конца транзакции
+func (*MSSQLAdapter) SavepointQueries(name string) (save, release, rollback string) {
+	return "SAVE TRANSACTION " + name, "", "ROLLBACK TRANSACTION " + name
+}
+
+func (*MSSQLAdapter) ExistsWrap() (prefix, suffix string) {
+	return "SELECT CASE WHEN EXISTS(", ") THEN 1 ELSE 0 END"
+}
+
+// writeMSSQLOutput - OUTPUT-секция с полями псевдотаблицы inserted или deleted
+func writeMSSQLOutput(sb *strings.Builder, dialect Dialect, pseudoTable string, list dbs.FieldInfoList) {
+	_, _ = sb.WriteString(" OUTPUT ")
+	writeNames(sb, dialect, list, pseudoTable)
+}
+
+// InsertManyQuery - многострочная вставка с OUTPUT inserted.*. SQL Server не гарантирует, что строки OUTPUT
+// следуют в порядке строк VALUES, поэтому ScanInsertManyRows для этого диалекта неприменима:
+// возвращённые строки следует сопоставлять записям по естественному ключу
+func (a *MSSQLAdapter) InsertManyQuery(info *dbs.StructInfo, rowCount int) (string, error) {
+	return insertManyQuery(a, info, rowCount, func() string {
+		var sb strings.Builder
+
+		allFields := info.AllFields()
+		nonAutoFields := info.NonAutoFields()
+		sb.Grow(50 + len(allFields)*3*DefaultFieldNameLength + rowCount*len(nonAutoFields)*8)
+		_, _ = sb.WriteString("INSERT INTO ")
+		writeTableName(&sb, a, info.TableName())
+		_, _ = sb.WriteString(" (")
+		writeNames(&sb, a, nonAutoFields, "")
+		_, _ = sb.WriteString(")")
+		writeMSSQLOutput(&sb, a, mssqlInserted, allFields)
+		writeInsertManyValues(&sb, a, nonAutoFields, rowCount)
+
+		return sb.String()
+	})
+}
+
+func (*MSSQLAdapter) InsertManyBatchSize(info *dbs.StructInfo) int {
+	return batchSizeByParams(info, mssqlMaxParams, mssqlMaxRows)
+}
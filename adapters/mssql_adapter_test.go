@@ -0,0 +1,40 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSSQLAdapter_Queries(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	var dialect adapters.Dialect = &adapters.MSSQLAdapter{}
+	assert.True(t, dialect.CanReturnValuesInDML())
+	assert.Equal(t, "@p2", dialect.Placeholder(2))
+	assert.Equal(t, "[Order]", dialect.QuoteIdent("Order"))
+	assert.Equal(t, "[a]]b]", dialect.QuoteIdent("a]b"))
+	assert.Equal(t,
+		"INSERT INTO test_rec (kind, name, aux_field)"+
+			" OUTPUT inserted.id, inserted.kind, inserted.name, inserted.aux_field VALUES (@p1, @p2, @p3)",
+		dialect.InsertOneQuery(si))
+	assert.Equal(t,
+		"SELECT TOP (1) id, kind, name, aux_field FROM test_rec WHERE id=@p1;",
+		dialect.SelectOneQuery(si))
+	assert.Equal(t,
+		"SELECT t.id, t.kind, t.name, t.aux_field, COUNT(*) OVER() FROM test_rec t",
+		selectMany(t, dialect, si, adapters.QueryOptions{WithTotals: true, WithAlias: "t"}))
+	assert.Equal(t,
+		"UPDATE test_rec SET kind=@p1, name=@p2, aux_field=@p3"+
+			" OUTPUT inserted.id, inserted.kind, inserted.name, inserted.aux_field WHERE id=@p4",
+		dialect.UpdateOneQuery(si))
+	assert.Equal(t,
+		"DELETE FROM test_rec OUTPUT deleted.id, deleted.kind, deleted.name, deleted.aux_field WHERE id=@p1",
+		dialect.DeleteOneQuery(si))
+}
@@ -0,0 +1,156 @@
+package adapters
+
+import (
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// MySQLAdapter - диалект MySQL/MariaDB.
+// СУБД не возвращает значения из DML, поэтому автогенерируемые поля после вставки
+// заполняются через InsertOneByLastInsertID.
+// Настройки задаются конструктором NewMySQLAdapter и не меняются, методы объявлены у указателя (см. PGAdapter)
+type MySQLAdapter struct {
+	cache QueryCache
+
+	quoting QuoteMode // Режим экранирования идентификаторов
+}
+
+var _ Dialect = (*MySQLAdapter)(nil)
+
+// NewMySQLAdapter - диалект MySQL с режимом экранирования идентификаторов quoting
+func NewMySQLAdapter(quoting QuoteMode) *MySQLAdapter {
+	return &MySQLAdapter{quoting: quoting}
+}
+
+func (*MySQLAdapter) Name() string {
+	return "mysql"
+}
+
+func (*MySQLAdapter) Placeholder(int) string {
+	return "?"
+}
+
+func (*MySQLAdapter) QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (a *MySQLAdapter) Ident(name string) string {
+	return identByMode(a, a.quoting, plainIdentRe, mysqlReservedWords, name)
+}
+
+func (*MySQLAdapter) CanReturnValuesInDML() bool {
+	return false
+}
+
+func (a *MySQLAdapter) QueryCache() *QueryCache {
+	return &a.cache
+}
+
+func (*MySQLAdapter) Paging(limit, offset int, _ bool) string {
+	return limitOffset(limit, offset, "18446744073709551615")
+}
+
+func (a *MySQLAdapter) LockClause(lock RowLock) (string, error) {
+	return lockClause(a, lock, false)
+}
+
+func (a *MySQLAdapter) InsertOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindInsertOne), func() string {
+		return buildInsertOne(a, info, false)
+	})
+}
+
+func (a *MySQLAdapter) SelectOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectOne), func() string {
+		return buildSelectOne(a, info)
+	})
+}
+
+func (a *MySQLAdapter) SelectOneLockedQuery(info *dbs.StructInfo, lock RowLock) (string, error) {
+	return selectOneLockedQuery(a, info, lock)
+}
+
+func (a *MySQLAdapter) SelectManyQuery(info *dbs.StructInfo, opts QueryOptions) (string, error) {
+	return selectManyQuery(a, &a.cache, info, opts, false)
+}
+
+func (a *MySQLAdapter) SelectWithRefsQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectWithRefs), func() string {
+		return buildSelectWithRefs(a, info)
+	})
+}
+
+func (a *MySQLAdapter) UpdateOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindUpdateOne), func() string {
+		return buildUpdateOne(a, info, false)
+	})
+}
+
+func (a *MySQLAdapter) UpdateFieldsQuery(info *dbs.StructInfo, fieldNames ...string) (string, error) {
+	setFields, err := updateFieldsList(info, fieldNames)
+	if err != nil {
+		return "", err
+	}
+	return a.cache.getOrPut(makeUpdateFieldsKey(info, fieldNames), func() string {
+		return buildUpdateFields(a, info, setFields, false)
+	}), nil
+}
+
+func (a *MySQLAdapter) DeleteOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindDeleteOne), func() string {
+		return buildDeleteOne(a, info, false)
+	})
+}
+
+func (a *MySQLAdapter) SelectByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindSelectByPKs, keyCount), func() string {
+		return buildSelectByPKs(a, info, keyCount)
+	})
+}
+
+func (a *MySQLAdapter) DeleteByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindDeleteByPKs, keyCount), func() string {
+		return buildDeleteByPKs(a, info, keyCount, false)
+	})
+}
+
+func (a *MySQLAdapter) UpdateWhereQuery(
+	info *dbs.StructInfo, where *Criteria, withReturning bool, fieldNames ...string,
+) (string, []any, error) {
+	setFields, conds, args, err := prepareUpdateWhere(a, info, where, withReturning, fieldNames)
+	if err != nil {
+		return "", nil, err
+	}
+	return buildUpdateWhere(a, info, setFields, conds, withReturning), args, nil
+}
+
+func (a *MySQLAdapter) DeleteWhereQuery(info *dbs.StructInfo, where *Criteria, withReturning bool) (string, []any, error) {
+	conds, args, err := whereConditions(a, info, where, 0, withReturning)
+	if err != nil {
+		return "", nil, err
+	}
+	return buildDeleteWhere(a, info, conds, withReturning), args, nil
+}
+
+func (a *MySQLAdapter) CountQuery(info *dbs.StructInfo, where *Criteria, mode CountMode) (string, []any, error) {
+	return countQuery(a, info, where, mode)
+}
+
+func (a *MySQLAdapter) ExistsByPKQuery(info *dbs.StructInfo) string {
+	return existsByPKQuery(a, info)
+}
+
+func (a *MySQLAdapter) ExistsWhereQuery(info *dbs.StructInfo, where *Criteria) (string, []any, error) {
+	return existsWhereQuery(a, info, where)
+}
+
+func (a *MySQLAdapter) InsertManyQuery(info *dbs.StructInfo, rowCount int) (string, error) {
+	return insertManyQuery(a, info, rowCount, func() string {
+		return buildInsertMany(a, info, rowCount, false)
+	})
+}
+
+func (*MySQLAdapter) InsertManyBatchSize(info *dbs.StructInfo) int {
+	return batchSizeByParams(info, mysqlMaxParams, 0)
+}
@@ -0,0 +1,85 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type AutoStampRec struct {
+	TestKey
+	TestBody
+
+	CreatedAt time.Time `dbs:"auto"`
+}
+
+func TestMySQLAdapter_Queries(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	var dialect adapters.Dialect = &adapters.MySQLAdapter{}
+	assert.False(t, dialect.CanReturnValuesInDML())
+	assert.Equal(t, "`Order`", dialect.QuoteIdent("Order"))
+	assert.Equal(t,
+		"INSERT INTO test_rec (kind, name, aux_field) VALUES (?, ?, ?)",
+		dialect.InsertOneQuery(si))
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field FROM test_rec WHERE id=? LIMIT 1;",
+		dialect.SelectOneQuery(si))
+	assert.Equal(t,
+		"SELECT t.id, t.kind, t.name, t.aux_field, COUNT(*) OVER() FROM test_rec t",
+		selectMany(t, dialect, si, adapters.QueryOptions{WithTotals: true, WithAlias: "t"}))
+	assert.Equal(t,
+		"UPDATE test_rec SET kind=?, name=?, aux_field=? WHERE id=?",
+		dialect.UpdateOneQuery(si))
+	assert.Equal(t,
+		"DELETE FROM test_rec WHERE id=?",
+		dialect.DeleteOneQuery(si))
+}
+
+func TestInsertOneByLastInsertID(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	db, conn := openFakeDB(t,
+		fakeResponse{LastInsertID: 42, RowsAffected: 1},
+		fakeResponse{Columns: []string{"id", "created_at"}, Rows: [][]driver.Value{{int64(42), created}}},
+	)
+
+	si, err := dbs.NewStructInfo(AutoStampRec{})
+	require.NoError(t, err)
+
+	rec := AutoStampRec{TestBody: TestBody{Kind: 3, Name: "three"}}
+	require.NoError(t, adapters.InsertOneByLastInsertID(t.Context(), db, &adapters.MySQLAdapter{}, si, &rec))
+
+	assert.Equal(t, int64(42), rec.ID)
+	assert.Equal(t, created, rec.CreatedAt)
+
+	calls := conn.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "INSERT INTO auto_stamp_rec (kind, name) VALUES (?, ?)", calls[0].Query)
+	assert.Equal(t, "SELECT id, created_at FROM auto_stamp_rec WHERE id=?", calls[1].Query)
+	assert.Len(t, calls[1].Args, 1)
+}
+
+func TestInsertOneByLastInsertID_OnlyPK(t *testing.T) {
+	t.Parallel()
+
+	db, conn := openFakeDB(t, fakeResponse{LastInsertID: 7, RowsAffected: 1})
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	rec := TestRec{TestBody: TestBody{Kind: 1, Name: "one"}}
+	require.NoError(t, adapters.InsertOneByLastInsertID(t.Context(), db, &adapters.MySQLAdapter{}, si, &rec))
+
+	assert.Equal(t, int64(7), rec.ID)
+	assert.Len(t, conn.Calls(), 1)
+}
@@ -1,127 +1,169 @@
 package adapters
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/mirrorru/dbs"
 )
 
+// PGAdapter - диалект PostgreSQL.
+// Настройки задаются конструктором и не меняются, поэтому запросы в кэше экземпляра им всегда соответствуют;
+// нулевое значение - настройки по умолчанию. Экземпляр владеет кэшем запросов, поэтому методы диалекта
+// объявлены у указателя и вызываются у &PGAdapter{} или NewPGAdapter, а не у значения PGAdapter{}
 type PGAdapter struct {
+	cache QueryCache
+
+	quoting QuoteMode // Режим экранирования идентификаторов
+}
+
+var _ Dialect = (*PGAdapter)(nil)
+
+// NewPGAdapter - диалект PostgreSQL с режимом экранирования идентификаторов quoting
+func NewPGAdapter(quoting QuoteMode) *PGAdapter {
+	return &PGAdapter{quoting: quoting}
+}
+
+func (*PGAdapter) Name() string {
+	return "postgres"
+}
+
+func (*PGAdapter) Placeholder(idx int) string {
+	return "$" + strconv.Itoa(idx)
+}
+
+func (*PGAdapter) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (a *PGAdapter) Ident(name string) string {
+	return identByMode(a, a.quoting, lowerIdentRe, pgReservedWords, name)
 }
 
-func (PGAdapter) CanReturnValuesInDML() bool {
+func (*PGAdapter) CanReturnValuesInDML() bool {
 	return true
 }
 
-func (PGAdapter) InsertOneQuery(info *dbs.StructInfo) string {
-	return queryCache.GetOrPut(queryCacheKey{Type: info.Type(), Kind: queryKindInsertOne}, func() string {
-		var sb strings.Builder
+func (*PGAdapter) arrayParams() {}
 
-		allFields := info.AllFields()
-		sb.Grow(50 + len(allFields)*3*DefaultFieldNameLength)
-		_, _ = sb.WriteString("INSERT INTO ")
-		_, _ = sb.WriteString(info.TableName())
-		_, _ = sb.WriteString(" (")
-		WriteFieldInfoListNames(&sb, info.NonAutoFields(), ", ")
-		_, _ = sb.WriteString(") VALUES ($")
-		WriteFieldInfoListIdxs(&sb, info.NonAutoFields(), 1, ", $")
-		_, _ = sb.WriteString(") RETURNING ")
-		WriteFieldInfoListNames(&sb, allFields, ", ")
+func (a *PGAdapter) QueryCache() *QueryCache {
+	return &a.cache
+}
+
+func (*PGAdapter) Paging(limit, offset int, _ bool) string {
+	return limitOffset(limit, offset, "")
+}
 
-		return sb.String()
+func (a *PGAdapter) LockClause(lock RowLock) (string, error) {
+	return lockClause(a, lock, true)
+}
+
+func (a *PGAdapter) InsertOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindInsertOne), func() string {
+		return buildInsertOne(a, info, true)
 	})
 }
 
-func (PGAdapter) SelectOneQuery(info *dbs.StructInfo) string {
-	return queryCache.GetOrPut(queryCacheKey{Type: info.Type(), Kind: queryKindSelectOne}, func() string {
-		var sb strings.Builder
+func (a *PGAdapter) SelectOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectOne), func() string {
+		return buildSelectOne(a, info)
+	})
+}
 
-		allFields := info.AllFields()
-		pkFields := info.PKFields()
-		sb.Grow(30 + len(allFields)*2*DefaultFieldNameLength + len(pkFields)*DefaultFieldNameLength)
+func (a *PGAdapter) SelectOneLockedQuery(info *dbs.StructInfo, lock RowLock) (string, error) {
+	return selectOneLockedQuery(a, info, lock)
+}
 
-		_, _ = sb.WriteString("SELECT ")
-		WriteFieldInfoListNames(&sb, allFields, ", ")
-		_, _ = sb.WriteString(" FROM ")
-		_, _ = sb.WriteString(info.TableName())
-		_, _ = sb.WriteString(" WHERE ")
-		WriteFieldInfoListEQs(&sb, pkFields, 1, " AND ")
-		_, _ = sb.WriteString(" LIMIT 1;")
+func (a *PGAdapter) SelectManyQuery(info *dbs.StructInfo, opts QueryOptions) (string, error) {
+	return selectManyQuery(a, &a.cache, info, opts, true)
+}
 
-		return sb.String()
+func (a *PGAdapter) SelectWithRefsQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectWithRefs), func() string {
+		return buildSelectWithRefs(a, info)
 	})
 }
 
-func (PGAdapter) SelectManyQuery(info *dbs.StructInfo, opts QueryOptions) string {
-	return queryCache.GetOrPut(
-		queryCacheKey{Type: info.Type(), Kind: queryKindSelectOne, QueryOptions: opts},
-		func() string {
-			var sb strings.Builder
-
-			allFields := info.AllFields()
-			sb.Grow(30 + (len(allFields)+1)*2*(DefaultFieldNameLength+len(opts.WithAlias)))
-
-			prefix := ", "
-			_, _ = sb.WriteString("SELECT ")
-			if len(opts.WithAlias) > 0 {
-				_, _ = sb.WriteString(opts.WithAlias)
-				_, _ = sb.WriteString(".")
-				prefix += opts.WithAlias + "."
-			}
-			WriteFieldInfoListNames(&sb, allFields, prefix)
-			if opts.WithTotals {
-				_, _ = sb.WriteString(", COUNT(")
-				if len(opts.WithAlias) > 0 {
-					_, _ = sb.WriteString(opts.WithAlias)
-					_, _ = sb.WriteString(".")
-				}
-				_, _ = sb.WriteString("*) OVER()")
-			}
-			_, _ = sb.WriteString(" FROM ")
-			_, _ = sb.WriteString(info.TableName())
-			if len(opts.WithAlias) > 0 {
-				_, _ = sb.WriteString(" ")
-				_, _ = sb.WriteString(opts.WithAlias)
-			}
-
-			return sb.String()
-		})
-}
-
-func (PGAdapter) UpdateOneQuery(info *dbs.StructInfo) string {
-	return queryCache.GetOrPut(queryCacheKey{Type: info.Type(), Kind: queryKindUpdateOne}, func() string {
-		var sb strings.Builder
-
-		allFields := info.AllFields()
-		nonPkFields := info.NonPKFields()
-		sb.Grow(40 + len(allFields)*3*DefaultFieldNameLength)
-		_, _ = sb.WriteString("UPDATE ")
-		_, _ = sb.WriteString(info.TableName())
-		_, _ = sb.WriteString(" SET ")
-		WriteFieldInfoListEQs(&sb, nonPkFields, 1, ", ")
-		_, _ = sb.WriteString(" WHERE ")
-		WriteFieldInfoListEQs(&sb, info.PKFields(), len(nonPkFields)+1, " AND ")
-		_, _ = sb.WriteString(" RETURNING ")
-		WriteFieldInfoListNames(&sb, allFields, ", ")
-
-		return sb.String()
+func (a *PGAdapter) UpdateOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindUpdateOne), func() string {
+		return buildUpdateOne(a, info, true)
 	})
 }
 
-func (PGAdapter) DeleteOneQuery(info *dbs.StructInfo) string {
-	return queryCache.GetOrPut(queryCacheKey{Type: info.Type(), Kind: queryKindDeleteOne}, func() string {
-		var sb strings.Builder
+func (a *PGAdapter) UpdateFieldsQuery(info *dbs.StructInfo, fieldNames ...string) (string, error) {
+	setFields, err := updateFieldsList(info, fieldNames)
+	if err != nil {
+		return "", err
+	}
+	return a.cache.getOrPut(makeUpdateFieldsKey(info, fieldNames), func() string {
+		return buildUpdateFields(a, info, setFields, true)
+	}), nil
+}
 
-		allFields := info.AllFields()
-		pkFields := info.PKFields()
-		sb.Grow(20 + len(pkFields)*2*DefaultFieldNameLength)
-		_, _ = sb.WriteString("DELETE FROM ")
-		_, _ = sb.WriteString(info.TableName())
-		_, _ = sb.WriteString(" WHERE ")
-		WriteFieldInfoListEQs(&sb, info.PKFields(), 1, " AND ")
-		_, _ = sb.WriteString(" RETURNING ")
-		WriteFieldInfoListNames(&sb, allFields, ", ")
+func (a *PGAdapter) DeleteOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindDeleteOne), func() string {
+		return buildDeleteOne(a, info, true)
+	})
+}
+
+func (a *PGAdapter) SelectByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindSelectByPKs, keyCount), func() string {
+		return buildSelectByPKs(a, info, keyCount)
+	})
+}
+
+func (a *PGAdapter) DeleteByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindDeleteByPKs, keyCount), func() string {
+		return buildDeleteByPKs(a, info, keyCount, true)
+	})
+}
+
+func (a *PGAdapter) UpdateWhereQuery(
+	info *dbs.StructInfo, where *Criteria, withReturning bool, fieldNames ...string,
+) (string, []any, error) {
+	setFields, conds, args, err := prepareUpdateWhere(a, info, where, withReturning, fieldNames)
+	if err != nil {
+		return "", nil, err
+	}
+	return buildUpdateWhere(a, info, setFields, conds, withReturning), args, nil
+}
+
+func (a *PGAdapter) DeleteWhereQuery(info *dbs.StructInfo, where *Criteria, withReturning bool) (string, []any, error) {
+	conds, args, err := whereConditions(a, info, where, 0, withReturning)
+	if err != nil {
+		return "", nil, err
+	}
+	return buildDeleteWhere(a, info, conds, withReturning), args, nil
+}
 
-		return sb.String()
+func (a *PGAdapter) CountQuery(info *dbs.StructInfo, where *Criteria, mode CountMode) (string, []any, error) {
+	return countQuery(a, info, where, mode)
+}
+
+func (a *PGAdapter) ExistsByPKQuery(info *dbs.StructInfo) string {
+	return existsByPKQuery(a, info)
+}
+
+func (a *PGAdapter) ExistsWhereQuery(info *dbs.StructInfo, where *Criteria) (string, []any, error) {
+	return existsWhereQuery(a, info, where)
+}
+
+// ApproxCountQuery - оценка количества записей по статистике планировщика
+func (a *PGAdapter) ApproxCountQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindApproxCount), func() string {
+		var table strings.Builder
+		writeTableName(&table, a, info.TableName())
+		return "SELECT GREATEST(reltuples, 0)::bigint FROM pg_class WHERE oid = '" +
+			strings.ReplaceAll(table.String(), "'", "''") + "'::regclass"
 	})
 }
+
+func (a *PGAdapter) InsertManyQuery(info *dbs.StructInfo, rowCount int) (string, error) {
+	return insertManyQuery(a, info, rowCount, func() string {
+		return buildInsertMany(a, info, rowCount, true)
+	})
+}
+
+func (*PGAdapter) InsertManyBatchSize(info *dbs.StructInfo) int {
+	return batchSizeByParams(info, pgMaxParams, 0)
+}
@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// ClaimOptions - параметры захвата очередных записей таблицы-очереди (см. ClaimQuery)
+type ClaimOptions struct {
+	Where     *Criteria  // Условия отбора свободных записей, nil - любые записи
+	OrderBy   []SortSpec // Очерёдность захвата, по умолчанию - по PK
+	Limit     int        // Наибольшее количество захватываемых записей
+	Lock      RowLock    // Блокировка отбираемых строк, по умолчанию - FOR UPDATE SKIP LOCKED
+	SetFields []string   // Поля, значения которых записываются в захваченные записи
+}
+
+var errClaimLimit = fmt.Errorf("%w: claim needs positive limit", ErrInvalidOptions)
+
+// ClaimQuery - захват до opts.Limit очередных записей одним запросом: отбор по условиям с сортировкой,
+// ограничением и блокировкой строк, обновление полей opts.SetFields и возврат AllFields захваченных записей.
+// С блокировкой SKIP LOCKED параллельные обработчики захватывают разные записи.
+// Возвращает запрос и параметры условий, параметры запроса - UpdateWhereArgs
+func (a *PGAdapter) ClaimQuery(info *dbs.StructInfo, opts ClaimOptions) (string, []any, error) {
+	if opts.Limit <= 0 {
+		return "", nil, errClaimLimit
+	}
+	setFields, err := updateFieldsList(info, opts.SetFields)
+	if err != nil {
+		return "", nil, err
+	}
+	conds, args, err := criteriaConditions(a, info, opts.Where, len(setFields))
+	if err != nil {
+		return "", nil, err
+	}
+	order := QueryOptions{OrderBy: opts.OrderBy, Keyset: len(opts.OrderBy) == 0}
+	if err = order.ValidateFor(info); err != nil {
+		return "", nil, err
+	}
+	lock := opts.Lock
+	if lock.IsZero() {
+		lock = RowLock{Strength: LockForUpdate, Wait: LockSkipLocked}
+	}
+	lockClause, err := a.LockClause(lock)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	pkFields := info.PKFields()
+	sb.Grow(80 + (len(allFields)+len(setFields)+3*len(pkFields))*2*DefaultFieldNameLength + len(conds))
+	_, _ = sb.WriteString("UPDATE ")
+	writeTableName(&sb, a, info.TableName())
+	_, _ = sb.WriteString(" SET ")
+	writeEQs(&sb, a, setFields, 1, ", ")
+	_, _ = sb.WriteString(" WHERE (")
+	writeNames(&sb, a, pkFields, "")
+	_, _ = sb.WriteString(") IN (SELECT ")
+	writeNames(&sb, a, pkFields, "")
+	_, _ = sb.WriteString(" FROM ")
+	writeTableName(&sb, a, info.TableName())
+	if conds != "" {
+		_, _ = sb.WriteString(" WHERE ")
+		_, _ = sb.WriteString(conds)
+	}
+	writeOrderBy(&sb, a, info, order, "")
+	_, _ = sb.WriteString(" LIMIT ")
+	_, _ = sb.WriteString(strconv.Itoa(opts.Limit))
+	_, _ = sb.WriteString(lockClause)
+	_, _ = sb.WriteString(")")
+	writeReturning(&sb, a, allFields, true)
+
+	return sb.String(), args, nil
+}
+
+// ClaimNext - захватить до opts.Limit очередных записей, записав в них значения полей opts.SetFields записи src.
+// Возвращает захваченные записи в их новом состоянии
+func ClaimNext[T any](
+	ctx context.Context, db SQLQuerier, dialect *PGAdapter, info *dbs.StructInfo, src *T, opts ClaimOptions,
+) ([]T, error) {
+	query, whereArgs, err := dialect.ClaimQuery(info, opts)
+	if err != nil {
+		return nil, err
+	}
+	args, err := UpdateWhereArgs(info, src, whereArgs, opts.SetFields...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	claimed, _, err := CollectMany[T](rows, info, QueryOptions{})
+	return claimed, err
+}
@@ -0,0 +1,82 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Job struct {
+	ID     int64 `dbs:"auto;pk"`
+	Status string
+	Worker string
+}
+
+func TestPGAdapter_ClaimQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Job{})
+	require.NoError(t, err)
+
+	pg := &adapters.PGAdapter{}
+	query, args, err := pg.ClaimQuery(si, adapters.ClaimOptions{
+		Where:     adapters.Where(si).Eq("status", "new"),
+		Limit:     10,
+		SetFields: []string{"status", "worker"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"UPDATE job SET status=$1, worker=$2 WHERE (id) IN (SELECT id FROM job WHERE status=$3"+
+			" ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED) RETURNING id, status, worker",
+		query)
+	assert.Equal(t, []any{"new"}, args)
+
+	query, _, err = pg.ClaimQuery(si, adapters.ClaimOptions{
+		OrderBy:   []adapters.SortSpec{adapters.Desc("id")},
+		Limit:     1,
+		Lock:      adapters.RowLock{Strength: adapters.LockForNoKeyUpdate, Wait: adapters.LockNoWait},
+		SetFields: []string{"worker"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"UPDATE job SET worker=$1 WHERE (id) IN (SELECT id FROM job"+
+			" ORDER BY id DESC LIMIT 1 FOR NO KEY UPDATE NOWAIT) RETURNING id, status, worker",
+		query)
+
+	_, _, err = pg.ClaimQuery(si, adapters.ClaimOptions{SetFields: []string{"worker"}})
+	require.ErrorIs(t, err, adapters.ErrInvalidOptions)
+	_, _, err = pg.ClaimQuery(si, adapters.ClaimOptions{Limit: 1})
+	require.Error(t, err)
+}
+
+func TestClaimNext(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Job{})
+	require.NoError(t, err)
+
+	db, conn := openFakeDB(t, fakeResponse{
+		Columns: []string{"id", "status", "worker"},
+		Rows: [][]driver.Value{
+			{int64(3), "taken", "w1"},
+			{int64(4), "taken", "w1"},
+		},
+	})
+
+	src := Job{Status: "taken", Worker: "w1"}
+	jobs, err := adapters.ClaimNext(t.Context(), db, &adapters.PGAdapter{}, si, &src, adapters.ClaimOptions{
+		Where:     adapters.Where(si).Eq("status", "new"),
+		Limit:     2,
+		SetFields: []string{"status", "worker"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Job{{ID: 3, Status: "taken", Worker: "w1"}, {ID: 4, Status: "taken", Worker: "w1"}}, jobs)
+
+	calls := conn.Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{&src.Status, &src.Worker, "new"}, calls[0].Args)
+}
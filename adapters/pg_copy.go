@@ -0,0 +1,93 @@
+package adapters
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mirrorru/dbs"
+)
+
+// PGCopier - общий интерфейс *pgx.Conn, *pgxpool.Pool и pgx.Tx для загрузки данных через COPY
+type PGCopier interface {
+	CopyFrom(
+		ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource,
+	) (int64, error)
+}
+
+// CopyFromTable - имя таблицы для CopyFrom, схема отделяется точкой
+func CopyFromTable(info *dbs.StructInfo) pgx.Identifier {
+	return strings.Split(info.TableName(), ".")
+}
+
+// CopyFromColumns - имена загружаемых полей: все поля, кроме автогенерируемых
+func CopyFromColumns(info *dbs.StructInfo) []string {
+	nonAutoFields := info.NonAutoFields()
+	result := make([]string, len(nonAutoFields))
+	for idx := range nonAutoFields {
+		result[idx] = nonAutoFields[idx].Name
+	}
+	return result
+}
+
+// copyRowReader - чтение значений загружаемых полей по заранее полученным индексам,
+// без поиска полей по именам для каждой строки. Слайс значений переиспользуется между строками
+type copyRowReader struct {
+	indexes [][]int
+	values  []any
+}
+
+func newCopyRowReader(info *dbs.StructInfo) *copyRowReader {
+	nonAutoFields := info.NonAutoFields()
+	reader := &copyRowReader{
+		indexes: make([][]int, len(nonAutoFields)),
+		values:  make([]any, len(nonAutoFields)),
+	}
+	for idx := range nonAutoFields {
+		reader.indexes[idx] = nonAutoFields[idx].Index()
+	}
+	return reader
+}
+
+func (r *copyRowReader) read(src reflect.Value) []any {
+	for idx, index := range r.indexes {
+		r.values[idx] = src.FieldByIndex(index).Interface()
+	}
+	return r.values
+}
+
+// CopyFromSlice - источник данных CopyFrom из слайса структур
+func CopyFromSlice[T any](info *dbs.StructInfo, src []T) pgx.CopyFromSource {
+	reader := newCopyRowReader(info)
+	return pgx.CopyFromSlice(len(src), func(idx int) ([]any, error) {
+		return reader.read(reflect.ValueOf(&src[idx]).Elem()), nil
+	})
+}
+
+// CopyFromSeq - источник данных CopyFrom из итератора структур.
+// Функцию stop необходимо вызвать после завершения CopyFrom, в т.ч. при ошибке
+func CopyFromSeq[T any](info *dbs.StructInfo, seq iter.Seq[T]) (rowSrc pgx.CopyFromSource, stop func()) {
+	reader := newCopyRowReader(info)
+	next, stop := iter.Pull(seq)
+	return pgx.CopyFromFunc(func() ([]any, error) {
+		item, ok := next()
+		if !ok {
+			return nil, nil
+		}
+		return reader.read(reflect.ValueOf(&item).Elem()), nil
+	}), stop
+}
+
+// CopyFrom - загрузить слайс структур в таблицу через COPY
+func CopyFrom[T any](ctx context.Context, conn PGCopier, info *dbs.StructInfo, src []T) (int64, error) {
+	return conn.CopyFrom(ctx, CopyFromTable(info), CopyFromColumns(info), CopyFromSlice(info, src))
+}
+
+// CopyFromIter - загрузить структуры из итератора в таблицу через COPY
+func CopyFromIter[T any](ctx context.Context, conn PGCopier, info *dbs.StructInfo, seq iter.Seq[T]) (int64, error) {
+	rowSrc, stop := CopyFromSeq(info, seq)
+	defer stop()
+	return conn.CopyFrom(ctx, CopyFromTable(info), CopyFromColumns(info), rowSrc)
+}
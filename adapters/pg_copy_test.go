@@ -0,0 +1,62 @@
+package adapters_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCopier - собирает строки источника CopyFrom так же, как это делает pgx: значения читаются сразу
+type fakeCopier struct {
+	table   pgx.Identifier
+	columns []string
+	rows    [][]any
+}
+
+func (c *fakeCopier) CopyFrom(
+	_ context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource,
+) (int64, error) {
+	c.table, c.columns = tableName, columnNames
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+		c.rows = append(c.rows, slices.Clone(values))
+	}
+	return int64(len(c.rows)), rowSrc.Err()
+}
+
+func TestCopyFrom(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	stamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recs := []TestRec{
+		{TestKey: TestKey{ID: 1}, TestBody: TestBody{Kind: 1, Name: "one"}, AuxField: stamp},
+		{TestKey: TestKey{ID: 2}, TestBody: TestBody{Kind: 2, Name: "two"}, AuxField: stamp},
+	}
+
+	copier := &fakeCopier{}
+	count, err := adapters.CopyFrom(t.Context(), copier, si, recs)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, pgx.Identifier{"test_rec"}, copier.table)
+	assert.Equal(t, []string{"kind", "name", "aux_field"}, copier.columns)
+	assert.Equal(t, [][]any{{uint(1), "one", stamp}, {uint(2), "two", stamp}}, copier.rows)
+
+	copier = &fakeCopier{}
+	count, err = adapters.CopyFromIter(t.Context(), copier, si, slices.Values(recs))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, [][]any{{uint(1), "one", stamp}, {uint(2), "two", stamp}}, copier.rows)
+}
@@ -0,0 +1,170 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mirrorru/dbs"
+)
+
+// TxBeginner - общий интерфейс *sql.DB и *sql.Conn для открытия транзакций
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+var (
+	_ TxBeginner = (*sql.DB)(nil)
+	_ TxBeginner = (*sql.Conn)(nil)
+)
+
+const (
+	defaultCursorName  = "dbs_cursor"
+	defaultCursorChunk = 1000
+)
+
+// CursorOptions - параметры выгрузки записей через серверный курсор (см. CursorIter)
+type CursorOptions struct {
+	Name      string       // Имя курсора, по умолчанию - dbs_cursor
+	ChunkSize int          // Количество строк, получаемых одним FETCH, по умолчанию - 1000
+	Query     QueryOptions // Сортировка и пагинация выборки, без WithTotals
+	Where     *Criteria    // Условия отбора, nil - все записи
+	Reuse     bool         // Читать все строки в одну запись (см. IterRows)
+}
+
+var errCursorChunk = fmt.Errorf("%w: negative cursor chunk size", ErrInvalidOptions)
+
+// DeclareCursorQuery - объявление курсора name для выборки SelectManyWhere с параметрами opts и условиями where.
+// Курсор без WITH HOLD существует до конца транзакции. Возвращает запрос и параметры условий
+func (a *PGAdapter) DeclareCursorQuery(
+	info *dbs.StructInfo, name string, opts QueryOptions, where *Criteria,
+) (string, []any, error) {
+	if opts.WithTotals {
+		return "", nil, errStreamTotals
+	}
+	query, args, err := SelectManyWhere(a, info, opts, where)
+	if err != nil {
+		return "", nil, err
+	}
+	return "DECLARE " + a.Ident(name) + " NO SCROLL CURSOR FOR " + strings.TrimSuffix(query, ";"), args, nil
+}
+
+// FetchCursorQuery - получение очередных count строк курсора name
+func (a *PGAdapter) FetchCursorQuery(name string, count int) string {
+	return "FETCH FORWARD " + strconv.Itoa(count) + " FROM " + a.Ident(name)
+}
+
+// CloseCursorQuery - закрытие курсора name
+func (a *PGAdapter) CloseCursorQuery(name string) string {
+	return "CLOSE " + a.Ident(name)
+}
+
+// CursorIter - построчное чтение записей T через серверный курсор: открывается транзакция только для чтения,
+// курсор объявляется для выборки по opts.Where и opts.Query, строки получаются порциями по opts.ChunkSize,
+// поэтому в памяти одновременно находится не больше одной порции. Транзакция открывается при начале перебора
+// и завершается по его окончании, в том числе при выходе из цикла досрочно.
+// Ошибка передаётся последним элементом перебора. После досрочного выхода элементов больше нет,
+// поэтому ошибка отката транзакции в этом случае не передаётся
+func CursorIter[T any](
+	ctx context.Context, db TxBeginner, dialect *PGAdapter, info *dbs.StructInfo, opts CursorOptions,
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		stopped := false
+		err := cursorIter(ctx, db, dialect, info, opts, func(item *T, err error) bool {
+			stopped = !yield(item, err)
+			return !stopped
+		})
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// cursorIter - перебор строк курсора; досрочный выход из перебора ошибкой не считается
+func cursorIter[T any](
+	ctx context.Context, db TxBeginner, dialect *PGAdapter, info *dbs.StructInfo, opts CursorOptions,
+	yield func(*T, error) bool,
+) (err error) {
+	name, chunk := opts.Name, opts.ChunkSize
+	switch {
+	case chunk < 0:
+		return errCursorChunk
+	case chunk == 0:
+		chunk = defaultCursorChunk
+	}
+	if name == "" {
+		name = defaultCursorName
+	}
+	declare, args, err := dialect.DeclareCursorQuery(info, name, opts.Query, opts.Where)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			err = errors.Join(err, ignoreTxDone(tx.Rollback()))
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, declare, args...); err != nil {
+		return err
+	}
+	fetch := dialect.FetchCursorQuery(name, chunk)
+	// Запись и приёмники при opts.Reuse общие для всех порций, а не создаются заново для каждого FETCH
+	row := &rowItem[T]{info: info, reuse: opts.Reuse}
+	for {
+		count := 0
+		for item, err := range iterRows(ctx, tx, row, fetch) {
+			if err != nil {
+				return err
+			}
+			count++
+			if !yield(item, nil) {
+				return nil
+			}
+		}
+		if count < chunk {
+			break
+		}
+	}
+	if _, err = tx.ExecContext(ctx, dialect.CloseCursorQuery(name)); err != nil {
+		return err
+	}
+	committed = true
+	return tx.Commit()
+}
+
+// ignoreTxDone - откат уже завершённой транзакции (например, при отмене контекста) ошибкой не считается
+func ignoreTxDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) || errors.Is(err, pgx.ErrTxClosed) {
+		return nil
+	}
+	return err
+}
+
+// ExportCursor - передать каждую запись, прочитанную через серверный курсор (см. CursorIter), функции fn.
+// Ошибка fn прекращает выгрузку и возвращается
+func ExportCursor[T any](
+	ctx context.Context, db TxBeginner, dialect *PGAdapter, info *dbs.StructInfo, opts CursorOptions,
+	fn func(item *T) error,
+) error {
+	for item, err := range CursorIter[T](ctx, db, dialect, info, opts) {
+		if err != nil {
+			return err
+		}
+		if err = fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
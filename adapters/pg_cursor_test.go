@@ -0,0 +1,133 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPGAdapter_CursorQueries(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	query, args, err := pg.DeclareCursorQuery(si, "export", adapters.QueryOptions{Keyset: true},
+		adapters.Where(si).Eq("name", "Ann"))
+	require.NoError(t, err)
+	assert.Equal(t, `DECLARE export NO SCROLL CURSOR FOR SELECT id, name, tags FROM authors WHERE name=$1 ORDER BY id`,
+		query)
+	assert.Equal(t, []any{"Ann"}, args)
+	assert.Equal(t, "FETCH FORWARD 100 FROM export", pg.FetchCursorQuery("export", 100))
+	assert.Equal(t, "CLOSE export", pg.CloseCursorQuery("export"))
+
+	_, _, err = pg.DeclareCursorQuery(si, "export", adapters.QueryOptions{WithTotals: true}, nil)
+	require.ErrorIs(t, err, adapters.ErrInvalidOptions)
+}
+
+func TestExportCursor(t *testing.T) {
+	t.Parallel()
+
+	columns := []string{"id", "name", "tags"}
+	db, conn := openFakeDB(t,
+		fakeResponse{},
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{{int64(1), "one", "{a}"}, {int64(2), "two", "{}"}}},
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{{int64(3), "three", "{c}"}}},
+		fakeResponse{},
+	)
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+
+	var (
+		names []string
+		items []*Author
+	)
+	opts := adapters.CursorOptions{ChunkSize: 2, Reuse: true}
+	err = adapters.ExportCursor(t.Context(), db, &adapters.PGAdapter{}, si, opts, func(item *Author) error {
+		names = append(names, item.Name)
+		items = append(items, item)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two", "three"}, names)
+	require.Len(t, items, 3)
+	assert.Same(t, items[0], items[2], "the item is reused across FETCH chunks")
+
+	calls := conn.Calls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, "DECLARE dbs_cursor NO SCROLL CURSOR FOR SELECT id, name, tags FROM authors", calls[0].Query)
+	assert.Equal(t, "FETCH FORWARD 2 FROM dbs_cursor", calls[1].Query)
+	assert.Equal(t, "FETCH FORWARD 2 FROM dbs_cursor", calls[2].Query)
+	assert.Equal(t, "CLOSE dbs_cursor", calls[3].Query)
+	assert.Equal(t, []string{"BEGIN", "COMMIT"}, conn.TxLog())
+}
+
+func TestCursorIter_Break(t *testing.T) {
+	t.Parallel()
+
+	columns := []string{"id", "name", "tags"}
+	db, conn := openFakeDB(t,
+		fakeResponse{},
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{{int64(1), "one", "{a}"}, {int64(2), "two", "{}"}}},
+	)
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+
+	opts := adapters.CursorOptions{Name: "authors_export", ChunkSize: 2}
+	for item, err := range adapters.CursorIter[Author](t.Context(), db, &adapters.PGAdapter{}, si, opts) {
+		require.NoError(t, err)
+		assert.Equal(t, Author{ID: 1, Name: "one", Tags: []string{"a"}}, *item)
+		break
+	}
+	assert.Len(t, conn.Calls(), 2)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.TxLog())
+	assert.Zero(t, db.Stats().InUse)
+}
+
+func TestCursorIter_BreakRollbackError(t *testing.T) {
+	t.Parallel()
+
+	db, conn := openFakeDB(t,
+		fakeResponse{},
+		fakeResponse{Columns: []string{"id", "name", "tags"}, Rows: [][]driver.Value{{int64(1), "one", "{}"}}},
+	)
+	conn.rollbackErr = errors.New("rollback failed")
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+
+	count := 0
+	require.NotPanics(t, func() {
+		for _, err := range adapters.CursorIter[Author](t.Context(), db, &adapters.PGAdapter{}, si, adapters.CursorOptions{}) {
+			require.NoError(t, err)
+			count++
+			break
+		}
+	}, "no yield after the consumer stopped")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.TxLog())
+}
+
+func TestExportCursor_Errors(t *testing.T) {
+	t.Parallel()
+
+	errFetch := errors.New("fetch failed")
+	db, conn := openFakeDB(t, fakeResponse{}, fakeResponse{Err: errFetch})
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	err = adapters.ExportCursor(t.Context(), db, pg, si, adapters.CursorOptions{}, func(*Author) error { return nil })
+	require.ErrorIs(t, err, errFetch)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.TxLog())
+
+	err = adapters.ExportCursor(t.Context(), db, pg, si, adapters.CursorOptions{ChunkSize: -1},
+		func(*Author) error { return nil })
+	require.ErrorIs(t, err, adapters.ErrInvalidOptions)
+	assert.Len(t, conn.TxLog(), 2, "invalid options don't open transaction")
+}
@@ -0,0 +1,94 @@
+package adapters
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// UpsertOptions - параметры вставки с обновлением при конфликте (INSERT ... ON CONFLICT)
+type UpsertOptions struct {
+	ConflictFields []string // Поля ограничения уникальности, по которому определяется конфликт; по умолчанию - PK
+	ExcludeFields  []string // Поля, не обновляемые при конфликте
+	DoNothing      bool     // При конфликте ничего не делать; RETURNING в этом случае не вернёт строку
+}
+
+var errNoConflictFields = errors.New("no conflict fields for upsert")
+
+// variant - представление опций для ключа кэша
+func (o UpsertOptions) variant() string {
+	return variantList(o.ConflictFields) + "|" + variantList(o.ExcludeFields) + "|" + strconv.FormatBool(o.DoNothing)
+}
+
+// UpsertOneQuery - вставка записи с обновлением при конфликте.
+// Обновляются поля, не входящие в PK, не автогенерируемые, не входящие в ограничение конфликта и не исключённые
+// опциями. Если обновлять нечего, поле конфликта присваивается само себе, чтобы RETURNING вернул строку
+func (a *PGAdapter) UpsertOneQuery(info *dbs.StructInfo, opts UpsertOptions) (string, error) {
+	conflictFields := info.PKFields()
+	if len(opts.ConflictFields) > 0 {
+		var err error
+		if conflictFields, err = peekFields(info, opts.ConflictFields); err != nil {
+			return "", err
+		}
+	}
+	if len(conflictFields) == 0 {
+		return "", errNoConflictFields
+	}
+	if _, err := peekFields(info, opts.ExcludeFields); err != nil {
+		return "", err
+	}
+
+	key := makeCacheKey(info, queryKindUpsertOne)
+	key.Variant = opts.variant()
+	return a.cache.getOrPut(key, func() string {
+		updateFields := info.NonPKFields().Filter(func(fi dbs.FieldInfo) bool {
+			return !fi.IsAutogen && !slices.Contains(opts.ExcludeFields, fi.Name) &&
+				!slices.ContainsFunc(conflictFields, func(cf dbs.FieldInfo) bool { return cf.Name == fi.Name })
+		})
+		if len(updateFields) == 0 {
+			updateFields = conflictFields[:1]
+		}
+
+		var sb strings.Builder
+
+		allFields := info.AllFields()
+		sb.Grow(80 + len(allFields)*5*DefaultFieldNameLength)
+		_, _ = sb.WriteString("INSERT INTO ")
+		writeTableName(&sb, a, info.TableName())
+		_, _ = sb.WriteString(" (")
+		writeNames(&sb, a, info.NonAutoFields(), "")
+		_, _ = sb.WriteString(") VALUES (")
+		writePlaceholders(&sb, a, info.NonAutoFields(), 1, ", ")
+		_, _ = sb.WriteString(") ON CONFLICT (")
+		writeNames(&sb, a, conflictFields, "")
+		if opts.DoNothing {
+			_, _ = sb.WriteString(") DO NOTHING")
+		} else {
+			_, _ = sb.WriteString(") DO UPDATE SET ")
+			for idx := range updateFields {
+				if idx > 0 {
+					_, _ = sb.WriteString(", ")
+				}
+				name := a.Ident(updateFields[idx].Name)
+				_, _ = sb.WriteString(name)
+				_, _ = sb.WriteString("=EXCLUDED.")
+				_, _ = sb.WriteString(name)
+			}
+		}
+		writeReturning(&sb, a, allFields, true)
+
+		return sb.String()
+	}), nil
+}
+
+// UpsertOneArgs - параметры UpsertOneQuery, совпадают с параметрами вставки
+func UpsertOneArgs[T any](info *dbs.StructInfo, src *T) ([]any, error) {
+	return InsertOneArgs(info, src)
+}
+
+func UpsertOneReceivers[T any](info *dbs.StructInfo, dest *T) ([]any, error) {
+	return info.AllFields().Refs(dest)
+}
@@ -0,0 +1,100 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type UpsertRec struct {
+	ID    int64  `dbs:"auto;pk"`
+	Code  string // уникальный код
+	Title string
+	Notes string
+}
+
+func TestPGAdapter_UpsertOneQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(UpsertRec{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	tests := []struct {
+		name string
+		opts adapters.UpsertOptions
+		need string
+	}{
+		{
+			name: "by PK",
+			need: "INSERT INTO upsert_rec (code, title, notes) VALUES ($1, $2, $3) ON CONFLICT (id)" +
+				" DO UPDATE SET code=EXCLUDED.code, title=EXCLUDED.title, notes=EXCLUDED.notes" +
+				" RETURNING id, code, title, notes",
+		},
+		{
+			name: "by unique field with excluded",
+			opts: adapters.UpsertOptions{ConflictFields: []string{"code"}, ExcludeFields: []string{"notes"}},
+			need: "INSERT INTO upsert_rec (code, title, notes) VALUES ($1, $2, $3) ON CONFLICT (code)" +
+				" DO UPDATE SET title=EXCLUDED.title RETURNING id, code, title, notes",
+		},
+		{
+			name: "nothing to update",
+			opts: adapters.UpsertOptions{ConflictFields: []string{"code"}, ExcludeFields: []string{"title", "notes"}},
+			need: "INSERT INTO upsert_rec (code, title, notes) VALUES ($1, $2, $3) ON CONFLICT (code)" +
+				" DO UPDATE SET code=EXCLUDED.code RETURNING id, code, title, notes",
+		},
+		{
+			name: "do nothing",
+			opts: adapters.UpsertOptions{ConflictFields: []string{"code"}, DoNothing: true},
+			need: "INSERT INTO upsert_rec (code, title, notes) VALUES ($1, $2, $3) ON CONFLICT (code)" +
+				" DO NOTHING RETURNING id, code, title, notes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			query, errQuery := pg.UpsertOneQuery(si, tt.opts)
+			require.NoError(t, errQuery)
+			assert.Equal(t, tt.need, query)
+		})
+	}
+}
+
+func TestPGAdapter_UpsertOneQuery_UnknownField(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(UpsertRec{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	_, err = pg.UpsertOneQuery(si, adapters.UpsertOptions{ConflictFields: []string{"missing"}})
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+	_, err = pg.UpsertOneQuery(si, adapters.UpsertOptions{ExcludeFields: []string{"missing"}})
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+}
+
+// commaRec - имя поля a,b совпадает с перечислением полей a и b через запятую
+type commaRec struct {
+	ID int64 `dbs:"pk"`
+	AB int   `dbs:"name:a,b"`
+	A  int   `dbs:"name:a"`
+	B  int   `dbs:"name:b"`
+}
+
+func TestUpsertOneQuery_VariantIsUnambiguous(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(commaRec{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	single, err := pg.UpsertOneQuery(si, adapters.UpsertOptions{ConflictFields: []string{"a,b"}})
+	require.NoError(t, err)
+	pair, err := pg.UpsertOneQuery(si, adapters.UpsertOptions{ConflictFields: []string{"a", "b"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, single, pair)
+	assert.Contains(t, pair, "ON CONFLICT (a, b)")
+}
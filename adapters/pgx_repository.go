@@ -0,0 +1,189 @@
+package adapters
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mirrorru/dbs"
+)
+
+// PGXQuerier - общий интерфейс *pgx.Conn, *pgxpool.Pool и pgx.Tx
+type PGXQuerier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+}
+
+var (
+	_ PGXQuerier = (*pgx.Conn)(nil)
+	_ PGXQuerier = (pgx.Tx)(nil)
+)
+
+// PGXRepository - типизированный доступ к таблице структуры T через pgx, аналог Repository.
+// Параметры и приёмники - указатели на поля записей без обёртки pq.Array: массивы и прочие типы
+// кодируются средствами pgx. Запросы можно как выполнять сразу, так и ставить в пакет pgx.Batch
+type PGXRepository[T any] struct {
+	db      PGXQuerier
+	dialect *PGAdapter
+	info    *dbs.StructInfo
+}
+
+// NewPGXRepository - репозиторий записей T, выполняющий запросы диалекта dialect через db.
+// pgx работает только с PostgreSQL, поэтому и диалект - только PostgreSQL
+func NewPGXRepository[T any](db PGXQuerier, dialect *PGAdapter) (*PGXRepository[T], error) {
+	var sample T
+	info, err := dbs.NewStructInfo(sample)
+	if err != nil {
+		return nil, err
+	}
+	return &PGXRepository[T]{db: db, dialect: dialect, info: info}, nil
+}
+
+// WithDB - копия репозитория, выполняющая запросы через db, например, через транзакцию pgx.Tx
+func (r *PGXRepository[T]) WithDB(db PGXQuerier) *PGXRepository[T] {
+	clone := *r
+	clone.db = db
+	return &clone
+}
+
+// FromContext - копия репозитория, выполняющая запросы в транзакции WithPGXTx из ctx, если она есть
+func (r *PGXRepository[T]) FromContext(ctx context.Context) *PGXRepository[T] {
+	if tx, ok := PGXTxFromContext(ctx); ok {
+		return r.WithDB(tx)
+	}
+	return r
+}
+
+// DB - выполняющий запросы *pgx.Conn, *pgxpool.Pool или pgx.Tx
+func (r *PGXRepository[T]) DB() PGXQuerier {
+	return r.db
+}
+
+// Dialect - диалект, формирующий запросы
+func (r *PGXRepository[T]) Dialect() *PGAdapter {
+	return r.dialect
+}
+
+// Info - описание структуры T
+func (r *PGXRepository[T]) Info() *dbs.StructInfo {
+	return r.info
+}
+
+// pgxRefs - указатели на поля списка list записи item
+func pgxRefs[T any](list dbs.FieldInfoList, item *T) []any {
+	rv := reflect.ValueOf(item).Elem()
+	result := make([]any, len(list), len(list)+1) // +1 для добавления оконных функций, если потребуется
+	for idx := range list {
+		result[idx] = rv.FieldByIndex(list[idx].Index()).Addr().Interface()
+	}
+	return result
+}
+
+// scanRow - приёмник результата запроса, возвращающего поля AllFields записи item
+func (r *PGXRepository[T]) scanRow(item *T) func(row pgx.Row) error {
+	return func(row pgx.Row) error {
+		return row.Scan(pgxRefs(r.info.AllFields(), item)...)
+	}
+}
+
+func (r *PGXRepository[T]) insert(item *T) (string, []any) {
+	return r.dialect.InsertOneQuery(r.info), pgxRefs(r.info.NonAutoFields(), item)
+}
+
+func (r *PGXRepository[T]) get(item *T) (string, []any) {
+	return r.dialect.SelectOneQuery(r.info), pgxRefs(r.info.PKFields(), item)
+}
+
+func (r *PGXRepository[T]) update(item *T) (string, []any) {
+	return r.dialect.UpdateOneQuery(r.info), append(pgxRefs(r.info.NonPKFields(), item), pgxRefs(r.info.PKFields(), item)...)
+}
+
+func (r *PGXRepository[T]) delete(item *T) (string, []any) {
+	return r.dialect.DeleteOneQuery(r.info), pgxRefs(r.info.PKFields(), item)
+}
+
+// exec - выполнить DML-запрос с чтением возвращённых полей в item
+func (r *PGXRepository[T]) exec(ctx context.Context, item *T, query string, args []any) error {
+	return r.scanRow(item)(r.db.QueryRow(ctx, query, args...))
+}
+
+// Insert - вставить запись item и заполнить её автогенерируемые поля
+func (r *PGXRepository[T]) Insert(ctx context.Context, item *T) error {
+	query, args := r.insert(item)
+	return r.exec(ctx, item, query, args)
+}
+
+// Get - прочитать в dest запись с PK, заданным в dest; отсутствие записи - ошибка pgx.ErrNoRows
+func (r *PGXRepository[T]) Get(ctx context.Context, dest *T) error {
+	query, args := r.get(dest)
+	return r.scanRow(dest)(r.db.QueryRow(ctx, query, args...))
+}
+
+// Update - обновить по PK поля записи item, не входящие в PK; отсутствие записи - ошибка pgx.ErrNoRows
+func (r *PGXRepository[T]) Update(ctx context.Context, item *T) error {
+	query, args := r.update(item)
+	return r.exec(ctx, item, query, args)
+}
+
+// Delete - удалить запись с PK, заданным в item, заполнив item значениями удалённой записи;
+// отсутствие записи - ошибка pgx.ErrNoRows
+func (r *PGXRepository[T]) Delete(ctx context.Context, item *T) error {
+	query, args := r.delete(item)
+	return r.exec(ctx, item, query, args)
+}
+
+// List - выбрать записи по условиям where (nil - все записи) с сортировкой и пагинацией из opts.
+// Возвращает записи и общее количество строк выборки (см. CollectMany)
+func (r *PGXRepository[T]) List(ctx context.Context, opts QueryOptions, where *Criteria) ([]T, int64, error) {
+	query, args, err := SelectManyWhere(r.dialect, r.info, opts, where)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	return collectMany(rows, opts, func(item *T) ([]any, error) {
+		return pgxRefs(r.info.AllFields(), item), nil
+	})
+}
+
+// queue - поставить DML-запрос в пакет с чтением возвращённых полей в item
+func (r *PGXRepository[T]) queue(batch *pgx.Batch, item *T, query string, args []any) {
+	batch.Queue(query, args...).QueryRow(r.scanRow(item))
+}
+
+// QueueInsert - поставить вставку item в пакет; item заполняется при закрытии результатов пакета (см. SendBatch)
+func (r *PGXRepository[T]) QueueInsert(batch *pgx.Batch, item *T) {
+	query, args := r.insert(item)
+	r.queue(batch, item, query, args)
+}
+
+// QueueGet - поставить чтение dest по PK в пакет
+func (r *PGXRepository[T]) QueueGet(batch *pgx.Batch, dest *T) {
+	query, args := r.get(dest)
+	batch.Queue(query, args...).QueryRow(r.scanRow(dest))
+}
+
+// QueueUpdate - поставить обновление item по PK в пакет
+func (r *PGXRepository[T]) QueueUpdate(batch *pgx.Batch, item *T) {
+	query, args := r.update(item)
+	r.queue(batch, item, query, args)
+}
+
+// QueueDelete - поставить удаление item по PK в пакет
+func (r *PGXRepository[T]) QueueDelete(batch *pgx.Batch, item *T) {
+	query, args := r.delete(item)
+	r.queue(batch, item, query, args)
+}
+
+// SendBatch - отправить пакет одним обменом с сервером и выполнить чтение результатов поставленных запросов.
+// Возвращает первую ошибку запросов пакета
+func (r *PGXRepository[T]) SendBatch(ctx context.Context, batch *pgx.Batch) error {
+	return r.db.SendBatch(ctx, batch).Close()
+}
@@ -0,0 +1,211 @@
+package adapters_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ adapters.PGXQuerier = (*pgxpool.Pool)(nil)
+
+// fakePGX - PGXQuerier, отвечающий заранее заданными строками и запоминающий запросы с параметрами
+type fakePGX struct {
+	responses [][][]any
+	calls     []fakeCall
+}
+
+func (f *fakePGX) next(query string, args []any) [][]any {
+	f.calls = append(f.calls, fakeCall{Query: query, Args: args})
+	if len(f.responses) == 0 {
+		return nil
+	}
+	resp := f.responses[0]
+	f.responses = f.responses[1:]
+	return resp
+}
+
+func (f *fakePGX) Exec(_ context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	f.next(query, args)
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (f *fakePGX) Query(_ context.Context, query string, args ...any) (pgx.Rows, error) {
+	return &fakePGXRows{rows: f.next(query, args), idx: -1}, nil
+}
+
+func (f *fakePGX) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	rows, _ := f.Query(ctx, query, args...)
+	return rows.(*fakePGXRows)
+}
+
+func (f *fakePGX) SendBatch(_ context.Context, batch *pgx.Batch) pgx.BatchResults {
+	return &fakePGXBatch{db: f, batch: batch}
+}
+
+// fakePGXBatch - результаты пакета: при закрытии выполняются обработчики поставленных запросов
+type fakePGXBatch struct {
+	db    *fakePGX
+	batch *pgx.Batch
+	idx   int
+}
+
+func (b *fakePGXBatch) current() *pgx.QueuedQuery {
+	qq := b.batch.QueuedQueries[b.idx]
+	b.idx++
+	return qq
+}
+
+func (b *fakePGXBatch) Exec() (pgconn.CommandTag, error) {
+	qq := b.current()
+	return b.db.Exec(context.Background(), qq.SQL, qq.Arguments...)
+}
+
+func (b *fakePGXBatch) Query() (pgx.Rows, error) {
+	qq := b.current()
+	return b.db.Query(context.Background(), qq.SQL, qq.Arguments...)
+}
+
+func (b *fakePGXBatch) QueryRow() pgx.Row {
+	qq := b.current()
+	return b.db.QueryRow(context.Background(), qq.SQL, qq.Arguments...)
+}
+
+func (b *fakePGXBatch) Close() error {
+	for b.idx < b.batch.Len() {
+		qq := b.batch.QueuedQueries[b.idx]
+		if qq.Fn == nil {
+			_, _ = b.Exec()
+			continue
+		}
+		if err := qq.Fn(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakePGXRows - строки ответа; значения присваиваются приёмникам без преобразований database/sql
+type fakePGXRows struct {
+	rows [][]any
+	idx  int
+}
+
+func (r *fakePGXRows) Close()                                       {}
+func (r *fakePGXRows) Err() error                                   { return nil }
+func (r *fakePGXRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakePGXRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakePGXRows) RawValues() [][]byte                          { return nil }
+func (r *fakePGXRows) Conn() *pgx.Conn                              { return nil }
+func (r *fakePGXRows) Values() ([]any, error)                       { return r.rows[r.idx], nil }
+
+func (r *fakePGXRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.rows)
+}
+
+func (r *fakePGXRows) Scan(dest ...any) error {
+	if r.idx < 0 && !r.Next() {
+		return pgx.ErrNoRows
+	}
+	for i, value := range r.rows[r.idx] {
+		target := reflect.ValueOf(dest[i]).Elem()
+		target.Set(reflect.ValueOf(value).Convert(target.Type()))
+	}
+	return nil
+}
+
+func TestPGXRepository(t *testing.T) {
+	t.Parallel()
+
+	stamp := time.Date(2025, 5, 6, 7, 8, 9, 0, time.UTC)
+	db := &fakePGX{responses: [][][]any{
+		{{int64(11), uint(1), "one", stamp}},
+		{{int64(11), uint(1), "one", stamp}},
+		{{int64(11), uint(2), "two", stamp}},
+		nil,
+		{{int64(11), uint(2), "two", stamp, int64(7)}},
+	}}
+	repo, err := adapters.NewPGXRepository[TestRec](db, &adapters.PGAdapter{})
+	require.NoError(t, err)
+
+	rec := TestRec{TestBody: TestBody{Kind: 1, Name: "one"}, AuxField: stamp}
+	require.NoError(t, repo.Insert(t.Context(), &rec))
+	assert.Equal(t, int64(11), rec.ID)
+
+	got := TestRec{TestKey: TestKey{ID: 11}}
+	require.NoError(t, repo.Get(t.Context(), &got))
+	assert.Equal(t, rec, got)
+
+	got.Kind, got.Name = 2, "two"
+	require.NoError(t, repo.Update(t.Context(), &got))
+
+	missing := TestRec{TestKey: TestKey{ID: 12}}
+	require.ErrorIs(t, repo.Delete(t.Context(), &missing), pgx.ErrNoRows)
+
+	opts := adapters.QueryOptions{WithTotals: true, Limit: 1}
+	list, total, err := repo.List(t.Context(), opts, adapters.Where(repo.Info()).Eq("kind", 2))
+	require.NoError(t, err)
+	assert.Equal(t, []TestRec{got}, list)
+	assert.Equal(t, int64(7), total)
+
+	require.Len(t, db.calls, 5)
+	assert.Equal(t, "INSERT INTO test_rec (kind, name, aux_field) VALUES ($1, $2, $3) RETURNING id, kind, name, aux_field",
+		db.calls[0].Query)
+	assert.Equal(t, []any{&rec.Kind, &rec.Name, &rec.AuxField}, db.calls[0].Args)
+	assert.Equal(t, []any{&got.Kind, &got.Name, &got.AuxField, &got.ID}, db.calls[2].Args)
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field, COUNT(*) OVER() FROM test_rec WHERE kind=$1 LIMIT 1", db.calls[4].Query)
+}
+
+func TestPGXRepository_NativeArrays(t *testing.T) {
+	t.Parallel()
+
+	db := &fakePGX{responses: [][][]any{{{int64(5), "Ann", []string{"a", "b"}}}}}
+	repo, err := adapters.NewPGXRepository[Author](db, &adapters.PGAdapter{})
+	require.NoError(t, err)
+
+	author := Author{Name: "Ann", Tags: []string{"a", "b"}}
+	require.NoError(t, repo.Insert(t.Context(), &author))
+	assert.Equal(t, Author{ID: 5, Name: "Ann", Tags: []string{"a", "b"}}, author)
+
+	require.Len(t, db.calls, 1)
+	assert.Equal(t, []any{&author.Name, &author.Tags}, db.calls[0].Args, "arrays are passed to pgx as is")
+}
+
+func TestPGXRepository_Batch(t *testing.T) {
+	t.Parallel()
+
+	db := &fakePGX{responses: [][][]any{
+		{{int64(1), uint(1), "one", time.Time{}}},
+		{{int64(2), uint(2), "two", time.Time{}}},
+		{{int64(3), uint(3), "three", time.Time{}}},
+	}}
+	repo, err := adapters.NewPGXRepository[TestRec](db, &adapters.PGAdapter{})
+	require.NoError(t, err)
+
+	recs := []TestRec{{TestBody: TestBody{Kind: 1, Name: "one"}}, {TestBody: TestBody{Kind: 2, Name: "two"}}}
+	got := TestRec{TestKey: TestKey{ID: 3}}
+
+	batch := &pgx.Batch{}
+	for i := range recs {
+		repo.QueueInsert(batch, &recs[i])
+	}
+	repo.QueueGet(batch, &got)
+	require.Equal(t, 3, batch.Len())
+	assert.Zero(t, recs[0].ID, "results are read on batch close")
+
+	require.NoError(t, repo.SendBatch(t.Context(), batch))
+	assert.Equal(t, int64(1), recs[0].ID)
+	assert.Equal(t, int64(2), recs[1].ID)
+	assert.Equal(t, "three", got.Name)
+	require.Len(t, db.calls, 3)
+	assert.Equal(t, "SELECT id, kind, name, aux_field FROM test_rec WHERE id=$1 LIMIT 1;", db.calls[2].Query)
+}
@@ -0,0 +1,198 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/mirrorru/dbs"
+)
+
+// Наибольшее количество ключей в одном запросе IN (...) для диалектов без параметров-массивов,
+// в пределах ограничения количества параметров любого из диалектов
+const preloadInChunk = 1000
+
+var (
+	errCompositeRefPreload = errors.New("preload supports only single-field references")
+	errPreloadItemType     = errors.New("preload items type doesn't match struct info")
+	errPreloadKeyType      = errors.New("preload needs comparable reference key type")
+)
+
+// Preload - дозагрузить записи, на которые ссылаются ссылочные поля (тег dbs:"ref") записей items.
+// Для каждой ссылаемой структуры выполняется один запрос по различным значениям внешних ключей,
+// найденные записи копируются в ссылочные поля-структуры и в структуры, на которые указывают поля-указатели.
+// Ссылки-указатели должны содержать значение ключа, nil-указатели пропускаются.
+// depth - глубина дозагрузки: 1 - только ссылки items, 2 - также ссылки дозагруженных записей и т.д.
+// T должен быть структурой, описанной info (не указателем на неё), а ключи ссылаемых структур - сравнимыми
+func Preload[T any](
+	ctx context.Context, db SQLQuerier, dialect Dialect, info *dbs.StructInfo, items []T, depth int,
+) error {
+	if itemType := reflect.TypeFor[T](); itemType != info.Type() {
+		return fmt.Errorf("%w [%s] for [%s]", errPreloadItemType, itemType, info.Type())
+	}
+	return preloadValues(ctx, db, dialect, info, reflect.ValueOf(items), depth)
+}
+
+func preloadValues(
+	ctx context.Context, db SQLQuerier, dialect Dialect, info *dbs.StructInfo, items reflect.Value, depth int,
+) error {
+	if depth <= 0 || items.Len() == 0 {
+		return nil
+	}
+	joins := refJoins(info)
+	targets := make([]*dbs.StructInfo, 0, len(joins))
+	for idx := range joins {
+		if len(joins[idx].fields) != 1 {
+			return fmt.Errorf("%w [%s]", errCompositeRefPreload, joins[idx].fields[0].Name)
+		}
+		// Значения ключей служат ключами map при отборе различных значений и раскладке найденных записей
+		if keyType := joins[idx].target.PKFields()[0].Type; !keyType.Comparable() {
+			return fmt.Errorf("%w [%s] in [%s]", errPreloadKeyType, keyType, joins[idx].target.TableName())
+		}
+		if !slices.Contains(targets, joins[idx].target) {
+			targets = append(targets, joins[idx].target)
+		}
+	}
+	for _, target := range targets {
+		if err := preloadTarget(ctx, db, dialect, items, joins, target, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preloadTarget - дозагрузить записи структуры target для всех ссылающихся на неё полей
+func preloadTarget(
+	ctx context.Context, db SQLQuerier, dialect Dialect,
+	items reflect.Value, joins []refJoin, target *dbs.StructInfo, depth int,
+) error {
+	keyIndex := target.PKFields()[0].Index()
+
+	var keys []any
+	seen := make(map[any]struct{}, items.Len())
+	forEachRef(items, joins, target, func(ref reflect.Value) {
+		key := ref.FieldByIndex(keyIndex).Interface()
+		if _, found := seen[key]; !found {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	})
+	if len(keys) == 0 {
+		return nil
+	}
+
+	chunkSize := preloadInChunk
+	if canPassArrays(dialect) {
+		chunkSize = len(keys)
+	}
+	loaded := reflect.MakeSlice(reflect.SliceOf(target.Type()), 0, len(keys))
+	for chunk := range slices.Chunk(keys, chunkSize) {
+		args, err := KeysArgs(dialect, target, chunk...)
+		if err != nil {
+			return err
+		}
+		query := dialect.SelectByPKsQuery(target, len(chunk))
+		if loaded, err = queryRefTargets(ctx, db, target, loaded, query, args); err != nil {
+			return err
+		}
+	}
+	if err := preloadValues(ctx, db, dialect, target, loaded, depth-1); err != nil {
+		return err
+	}
+
+	byKey := make(map[any]reflect.Value, loaded.Len())
+	for idx := range loaded.Len() {
+		item := loaded.Index(idx)
+		byKey[item.FieldByIndex(keyIndex).Interface()] = item
+	}
+	forEachRef(items, joins, target, func(ref reflect.Value) {
+		if item, found := byKey[ref.FieldByIndex(keyIndex).Interface()]; found {
+			ref.Set(item)
+		}
+	})
+	return nil
+}
+
+// forEachRef - обойти непустые ссылочные поля записей items, ссылающиеся на структуру target.
+// Для ссылок-указателей передаётся структура, на которую указывает поле
+func forEachRef(items reflect.Value, joins []refJoin, target *dbs.StructInfo, fn func(ref reflect.Value)) {
+	for itemIdx := range items.Len() {
+		item := items.Index(itemIdx)
+		for idx := range joins {
+			if joins[idx].target != target {
+				continue
+			}
+			ref := item.FieldByIndex(joins[idx].index)
+			if joins[idx].isPtr {
+				if ref.IsNil() {
+					continue
+				}
+				ref = ref.Elem()
+			}
+			fn(ref)
+		}
+	}
+}
+
+// queryRefTargets - выполнить запрос и добавить прочитанные записи структуры info к слайсу loaded
+func queryRefTargets(
+	ctx context.Context, db SQLQuerier, info *dbs.StructInfo, loaded reflect.Value, query string, args []any,
+) (reflect.Value, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return loaded, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		item := reflect.New(info.Type()).Elem()
+		receivers, complete := rowReceivers(info, item)
+		if err = rows.Scan(receivers...); err != nil {
+			return loaded, err
+		}
+		complete()
+		loaded = reflect.Append(loaded, item)
+	}
+	return loaded, rows.Err()
+}
+
+// rowReceivers - приёмники всех полей записи item. Значение внешнего ключа ссылки-указателя читается
+// отдельно и при непустом значении переносится complete во вновь созданную ссылаемую структуру
+func rowReceivers(info *dbs.StructInfo, item reflect.Value) (receivers []any, complete func()) {
+	allFields := info.AllFields()
+	receivers = make([]any, len(allFields))
+	var (
+		refIdxs []int
+		holders []reflect.Value
+	)
+	for idx := range allFields {
+		fld := &allFields[idx]
+		if fld.RefData == nil || fld.Type.Kind() != reflect.Ptr {
+			receivers[idx] = fieldReceiver(item.FieldByIndex(fld.Index()))
+			continue
+		}
+		keyField, _ := fld.RefData.StructInfo.PeekField(fld.RefData.FieldName)
+		holder := reflect.New(reflect.PointerTo(keyField.Type))
+		receivers[idx] = holder.Interface()
+		refIdxs = append(refIdxs, idx)
+		holders = append(holders, holder)
+	}
+
+	complete = func() {
+		for idx, fldIdx := range refIdxs {
+			fld := &allFields[fldIdx]
+			refField := item.FieldByIndex(fld.Index())
+			if holders[idx].Elem().IsNil() {
+				refField.SetZero()
+				continue
+			}
+			keyField, _ := fld.RefData.StructInfo.PeekField(fld.RefData.FieldName)
+			ref := reflect.New(fld.RefData.StructInfo.Type())
+			ref.Elem().FieldByIndex(keyField.Index()).Set(holders[idx].Elem().Elem())
+			refField.Set(ref)
+		}
+	}
+	return receivers, complete
+}
@@ -0,0 +1,110 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Shelf struct {
+	ID   int64 `dbs:"auto;pk"`
+	Book *Book `dbs:"ref"`
+}
+
+func (Shelf) TableName() string {
+	return "shelves"
+}
+
+func TestPreload(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Shelf{})
+	require.NoError(t, err)
+
+	db, conn := openFakeDB(t,
+		fakeResponse{Columns: []string{"id", "title", "author_id", "editor_id"}, Rows: [][]driver.Value{
+			{int64(1), "first", int64(10), int64(20)},
+			{int64(2), "second", nil, int64(10)},
+		}},
+		fakeResponse{Columns: []string{"id", "name", "tags"}, Rows: [][]driver.Value{
+			{int64(10), "Ann", nil},
+			{int64(20), "Ed", "{x}"},
+		}},
+	)
+
+	shelves := []Shelf{{ID: 1, Book: &Book{ID: 1}}, {ID: 2, Book: &Book{ID: 2}}, {ID: 3, Book: &Book{ID: 1}}, {ID: 4}}
+	require.NoError(t, adapters.Preload(t.Context(), db, &adapters.PGAdapter{}, si, shelves, 2))
+
+	calls := conn.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "SELECT id, title, author_id, editor_id FROM books WHERE id = ANY($1)", calls[0].Query)
+	assert.Equal(t, []any{pq.Array([]int64{1, 2})}, calls[0].Args)
+	assert.Equal(t, "SELECT id, name, tags FROM authors WHERE id = ANY($1)", calls[1].Query)
+	assert.Equal(t, []any{pq.Array([]int64{10, 20})}, calls[1].Args)
+
+	first := Book{
+		ID: 1, Title: "first",
+		Author: &Author{ID: 10, Name: "Ann"},
+		Editor: Author{ID: 20, Name: "Ed", Tags: []string{"x"}},
+	}
+	assert.Equal(t, first, *shelves[0].Book)
+	assert.Equal(t, first, *shelves[2].Book)
+	assert.Equal(t, Book{ID: 2, Title: "second", Editor: Author{ID: 10, Name: "Ann"}}, *shelves[1].Book)
+	assert.Nil(t, shelves[3].Book)
+}
+
+func TestPreload_InList(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Book{})
+	require.NoError(t, err)
+
+	db, conn := openFakeDB(t, fakeResponse{Columns: []string{"id", "name", "tags"}, Rows: [][]driver.Value{
+		{int64(20), "Ed", nil},
+	}})
+
+	books := []Book{{ID: 1, Author: &Author{ID: 10}, Editor: Author{ID: 20}}}
+	require.NoError(t, adapters.Preload(t.Context(), db, &adapters.MySQLAdapter{}, si, books, 1))
+
+	calls := conn.Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "SELECT id, name, tags FROM authors WHERE id IN (?, ?)", calls[0].Query)
+	assert.Equal(t, []any{int64(10), int64(20)}, calls[0].Args)
+	assert.Equal(t, []Book{{ID: 1, Author: &Author{ID: 10}, Editor: Author{ID: 20, Name: "Ed"}}}, books)
+
+	require.NoError(t, adapters.Preload(t.Context(), db, &adapters.MySQLAdapter{}, si, books, 0))
+	assert.Len(t, conn.Calls(), 1)
+}
+
+type blobKeyed struct {
+	Key []byte `dbs:"pk"`
+}
+
+type blobRef struct {
+	ID   int64      `dbs:"pk"`
+	Blob *blobKeyed `dbs:"ref"`
+}
+
+func TestPreload_Errors(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Book{})
+	require.NoError(t, err)
+	db, conn := openFakeDB(t)
+	pg := &adapters.PGAdapter{}
+
+	books := []*Book{{ID: 1, Author: &Author{ID: 10}}}
+	require.Error(t, adapters.Preload(t.Context(), db, pg, si, books, 1), "pointer items")
+	require.Error(t, adapters.Preload(t.Context(), db, pg, si, []Shelf{{ID: 1}}, 1), "mismatched info")
+
+	blobInfo, err := dbs.NewStructInfo(blobRef{})
+	require.NoError(t, err)
+	refs := []blobRef{{ID: 1, Blob: &blobKeyed{Key: []byte("k")}}}
+	require.Error(t, adapters.Preload(t.Context(), db, pg, blobInfo, refs, 1), "non-comparable key")
+	assert.Empty(t, conn.Calls())
+}
@@ -0,0 +1,165 @@
+package adapters
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// Построители запросов, общие для диалектов с синтаксисом, близким к стандартному
+
+func writePlaceholders(writer io.StringWriter, dialect Dialect, list dbs.FieldInfoList, startIdx int, separator string) {
+	for idx := range list {
+		if idx > 0 {
+			_, _ = writer.WriteString(separator)
+		}
+		_, _ = writer.WriteString(dialect.Placeholder(startIdx))
+		startIdx++
+	}
+}
+
+func writeEQs(writer io.StringWriter, dialect Dialect, list dbs.FieldInfoList, startIdx int, sepaPrefix string) {
+	for idx := range list {
+		if idx > 0 {
+			_, _ = writer.WriteString(sepaPrefix)
+		}
+		_, _ = writer.WriteString(dialect.Ident(list[idx].Name))
+		_, _ = writer.WriteString("=")
+		_, _ = writer.WriteString(dialect.Placeholder(startIdx))
+		startIdx++
+	}
+}
+
+func writeReturning(sb *strings.Builder, dialect Dialect, list dbs.FieldInfoList, withReturning bool) {
+	if withReturning {
+		_, _ = sb.WriteString(" RETURNING ")
+		writeNames(sb, dialect, list, "")
+	}
+}
+
+func buildInsertOne(dialect Dialect, info *dbs.StructInfo, withReturning bool) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(50 + len(allFields)*3*DefaultFieldNameLength)
+	_, _ = sb.WriteString("INSERT INTO ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" (")
+	writeNames(&sb, dialect, info.NonAutoFields(), "")
+	_, _ = sb.WriteString(") VALUES (")
+	writePlaceholders(&sb, dialect, info.NonAutoFields(), 1, ", ")
+	_, _ = sb.WriteString(")")
+	writeReturning(&sb, dialect, allFields, withReturning)
+
+	return sb.String()
+}
+
+func buildSelectOne(dialect Dialect, info *dbs.StructInfo) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	pkFields := info.PKFields()
+	sb.Grow(30 + len(allFields)*2*DefaultFieldNameLength + len(pkFields)*DefaultFieldNameLength)
+
+	_, _ = sb.WriteString("SELECT ")
+	writeNames(&sb, dialect, allFields, "")
+	_, _ = sb.WriteString(" FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" WHERE ")
+	writeEQs(&sb, dialect, pkFields, 1, " AND ")
+	_, _ = sb.WriteString(" LIMIT 1;")
+
+	return sb.String()
+}
+
+// buildSelectMany - выборка с условием keyset-пагинации и сортировкой, без LIMIT/OFFSET.
+// countByAlias задаёт форму подсчёта итогов: COUNT(alias.*) или COUNT(*)
+func buildSelectMany(dialect Dialect, info *dbs.StructInfo, opts QueryOptions, countByAlias bool) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(30 + (len(allFields)+1)*2*(DefaultFieldNameLength+len(opts.WithAlias)))
+
+	var alias string
+	if len(opts.WithAlias) > 0 {
+		alias = dialect.Ident(opts.WithAlias)
+	}
+	_, _ = sb.WriteString("SELECT ")
+	writeNames(&sb, dialect, allFields, alias)
+	if opts.WithTotals {
+		_, _ = sb.WriteString(", COUNT(")
+		if countByAlias && len(alias) > 0 {
+			_, _ = sb.WriteString(alias)
+			_, _ = sb.WriteString(".")
+		}
+		_, _ = sb.WriteString("*) OVER()")
+	}
+	_, _ = sb.WriteString(" FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	if len(alias) > 0 {
+		_, _ = sb.WriteString(" ")
+		_, _ = sb.WriteString(alias)
+	}
+	if opts.Keyset && len(opts.After) > 0 {
+		_, _ = sb.WriteString(" WHERE ")
+		writeKeysetCondition(&sb, dialect, info.PKFields(), alias, 1)
+	}
+	writeOrderBy(&sb, dialect, info, opts, alias)
+
+	return sb.String()
+}
+
+func buildUpdateOne(dialect Dialect, info *dbs.StructInfo, withReturning bool) string {
+	return buildUpdateFields(dialect, info, info.NonPKFields(), withReturning)
+}
+
+// buildUpdateFields - обновление полей setFields записи, найденной по PK
+func buildUpdateFields(dialect Dialect, info *dbs.StructInfo, setFields dbs.FieldInfoList, withReturning bool) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(40 + (len(allFields)+len(setFields))*2*DefaultFieldNameLength)
+	_, _ = sb.WriteString("UPDATE ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" SET ")
+	writeEQs(&sb, dialect, setFields, 1, ", ")
+	_, _ = sb.WriteString(" WHERE ")
+	writeEQs(&sb, dialect, info.PKFields(), len(setFields)+1, " AND ")
+	writeReturning(&sb, dialect, allFields, withReturning)
+
+	return sb.String()
+}
+
+func buildDeleteOne(dialect Dialect, info *dbs.StructInfo, withReturning bool) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	pkFields := info.PKFields()
+	sb.Grow(20 + len(pkFields)*2*DefaultFieldNameLength)
+	_, _ = sb.WriteString("DELETE FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" WHERE ")
+	writeEQs(&sb, dialect, pkFields, 1, " AND ")
+	writeReturning(&sb, dialect, allFields, withReturning)
+
+	return sb.String()
+}
+
+// buildSelectAuto - выборка автогенерируемых полей записи по первичному ключу
+func buildSelectAuto(dialect Dialect, info *dbs.StructInfo) string {
+	var sb strings.Builder
+
+	autoFields := info.AutoFields()
+	pkFields := info.PKFields()
+	sb.Grow(30 + len(autoFields)*DefaultFieldNameLength + len(pkFields)*DefaultFieldNameLength)
+
+	_, _ = sb.WriteString("SELECT ")
+	writeNames(&sb, dialect, autoFields, "")
+	_, _ = sb.WriteString(" FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" WHERE ")
+	writeEQs(&sb, dialect, pkFields, 1, " AND ")
+
+	return sb.String()
+}
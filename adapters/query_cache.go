@@ -0,0 +1,109 @@
+package adapters
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dot"
+)
+
+type queryKind byte
+
+const (
+	queryKindInsertOne queryKind = iota
+	queryKindSelectOne
+	queryKindSelectMany
+	queryKindUpdateOne
+	queryKindDeleteOne
+	queryKindSelectAuto
+	queryKindInsertMany
+	queryKindUpsertOne
+	queryKindUpdateFields
+	queryKindSelectWithRefs
+	queryKindSelectByPKs
+	queryKindDeleteByPKs
+	queryKindCount
+	queryKindApproxCount
+	queryKindExistsByPK
+	queryKindExistsWhere
+)
+
+type queryCacheKey struct {
+	Type    reflect.Type
+	Kind    queryKind
+	Rows    int    // Количество строк для многострочных запросов
+	Variant string // Представление параметров запроса, не сводимых к сравнимым значениям
+}
+
+type queryStore = dot.SyncStore[queryCacheKey, string]
+
+// variantList - однозначное представление списка имён для Variant: каждое имя предваряется своей длиной,
+// поэтому разные списки не дают одинаковых строк независимо от символов в именах
+func variantList(names []string) string {
+	var sb strings.Builder
+	for _, name := range names {
+		_, _ = sb.WriteString(strconv.Itoa(len(name)))
+		_, _ = sb.WriteString(":")
+		_, _ = sb.WriteString(name)
+	}
+	return sb.String()
+}
+
+// QueryCache - кэш сформированных запросов. Каждый экземпляр диалекта владеет собственным кэшем,
+// поэтому запросы разных диалектов (и разных настроек одного диалекта) не смешиваются.
+// Нулевое значение готово к использованию
+type QueryCache struct {
+	store atomic.Pointer[queryStore]
+}
+
+func makeCacheKey(info *dbs.StructInfo, kind queryKind) queryCacheKey {
+	return queryCacheKey{Type: info.Type(), Kind: kind}
+}
+
+func (c *QueryCache) storage() *queryStore {
+	if store := c.store.Load(); store != nil {
+		return store
+	}
+	c.store.CompareAndSwap(nil, &queryStore{})
+	return c.store.Load()
+}
+
+func (c *QueryCache) getOrPut(key queryCacheKey, maker func() string) string {
+	return c.storage().GetOrPut(key, maker)
+}
+
+// Len - количество запросов в кэше
+func (c *QueryCache) Len() int {
+	var count int
+	c.storage().ForEach(func(queryCacheKey, string) {
+		count++
+	})
+	return count
+}
+
+// Clear - очистить кэш
+func (c *QueryCache) Clear() {
+	c.store.Store(&queryStore{})
+}
+
+// WarmQueryCache - заранее сформировать запросы InsertOne, SelectOne, SelectMany (без опций),
+// UpdateOne и DeleteOne для типов samples, например, при старте сервиса
+func WarmQueryCache(dialect Dialect, samples ...any) error {
+	for _, sample := range samples {
+		info, err := dbs.NewStructInfo(sample)
+		if err != nil {
+			return err
+		}
+		dialect.InsertOneQuery(info)
+		dialect.SelectOneQuery(info)
+		if _, err = dialect.SelectManyQuery(info, QueryOptions{}); err != nil {
+			return err
+		}
+		dialect.UpdateOneQuery(info)
+		dialect.DeleteOneQuery(info)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache_Isolation(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	pg, mysql := &adapters.PGAdapter{}, &adapters.MySQLAdapter{}
+	assert.NotEqual(t, pg.InsertOneQuery(si), mysql.InsertOneQuery(si))
+	assert.Equal(t, 1, pg.QueryCache().Len())
+	assert.Equal(t, 1, mysql.QueryCache().Len())
+
+	// SelectOne и SelectMany без опций не должны делить одну запись кэша
+	assert.NotEqual(t, pg.SelectOneQuery(si), selectMany(t, pg, si, adapters.QueryOptions{}))
+	assert.Equal(t, 3, pg.QueryCache().Len())
+}
+
+func TestQueryCache_WarmAndClear(t *testing.T) {
+	t.Parallel()
+
+	dialect := &adapters.SQLiteAdapter{}
+	require.NoError(t, adapters.WarmQueryCache(dialect, TestRec{}, &AutoStampRec{}))
+	assert.Equal(t, 10, dialect.QueryCache().Len())
+
+	dialect.QueryCache().Clear()
+	assert.Equal(t, 0, dialect.QueryCache().Len())
+
+	require.Error(t, adapters.WarmQueryCache(dialect, 42))
+}
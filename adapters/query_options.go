@@ -0,0 +1,140 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// QueryOptions - параметры выборки многих записей
+type QueryOptions struct {
+	WithTotals bool   // Добавить к каждой строке общее количество строк выборки: COUNT(*) OVER()
+	WithAlias  string // Псевдоним таблицы
+
+	OrderBy []SortSpec // Сортировка
+	Limit   int        // Наибольшее количество строк, 0 - без ограничения
+	Offset  int        // Количество пропускаемых строк
+
+	// Keyset - постраничная выборка по первичному ключу: строки упорядочиваются по PK,
+	// а при заданном After выбираются строки с PK больше After. Не совместима с OrderBy
+	Keyset bool
+	// After - значения PK последней строки предыдущей страницы (см. KeysetAfter), параметры запроса
+	After []any
+
+	Lock RowLock // Блокировка выбранных строк, дописывается после LIMIT/OFFSET
+}
+
+// SortSpec - поле сортировки и её направление
+type SortSpec struct {
+	Field string
+	Desc  bool
+}
+
+// Asc - сортировка по возрастанию значений поля
+func Asc(field string) SortSpec {
+	return SortSpec{Field: field}
+}
+
+// Desc - сортировка по убыванию значений поля
+func Desc(field string) SortSpec {
+	return SortSpec{Field: field, Desc: true}
+}
+
+var (
+	ErrInvalidOptions = errors.New("invalid query options")
+
+	errNegativePaging = fmt.Errorf("%w: negative limit or offset", ErrInvalidOptions)
+	errKeysetOrderBy  = fmt.Errorf("%w: keyset pagination can't be combined with OrderBy", ErrInvalidOptions)
+	errKeysetNoPK     = fmt.Errorf("%w: keyset pagination needs PK fields", ErrInvalidOptions)
+)
+
+// Validate - проверить опции запроса на допустимость подстановки в SQL без сверки полей со структурой
+func (o QueryOptions) Validate() error {
+	if err := validateAlias(o.WithAlias); err != nil {
+		return err
+	}
+	if o.Limit < 0 || o.Offset < 0 {
+		return errNegativePaging
+	}
+	if o.Keyset && len(o.OrderBy) > 0 {
+		return errKeysetOrderBy
+	}
+	return o.Lock.Validate()
+}
+
+// ValidateFor - проверить опции запроса для структуры info, включая поля сортировки и значения keyset-пагинации
+func (o QueryOptions) ValidateFor(info *dbs.StructInfo) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	for _, spec := range o.OrderBy {
+		if _, found := info.PeekField(spec.Field); !found {
+			return fmt.Errorf("%w [%s] in [%s]", ErrUnknownField, spec.Field, info.TableName())
+		}
+	}
+	if o.Keyset {
+		pkCount := len(info.PKFields())
+		if pkCount == 0 {
+			return errKeysetNoPK
+		}
+		if len(o.After) > 0 && len(o.After) != pkCount {
+			return fmt.Errorf("%w: keyset After needs %d values", ErrInvalidOptions, pkCount)
+		}
+	}
+	return nil
+}
+
+// variant - представление опций, влияющих на кэшируемую часть запроса.
+// Limit, Offset и Lock дописываются к запросу после кэша и в представление не входят
+func (o QueryOptions) variant() string {
+	var sb strings.Builder
+	_, _ = sb.WriteString(o.WithAlias)
+	_, _ = sb.WriteString("|")
+	_, _ = sb.WriteString(strconv.FormatBool(o.WithTotals))
+	_, _ = sb.WriteString("|")
+	order := make([]string, len(o.OrderBy))
+	for idx, spec := range o.OrderBy {
+		if spec.Desc {
+			order[idx] = "-" + spec.Field
+		} else {
+			order[idx] = "+" + spec.Field
+		}
+	}
+	_, _ = sb.WriteString(variantList(order))
+	_, _ = sb.WriteString("|")
+	_, _ = sb.WriteString(strconv.FormatBool(o.Keyset))
+	_, _ = sb.WriteString(strconv.FormatBool(len(o.After) > 0))
+	return sb.String()
+}
+
+// isOrdered - будет ли в запросе ORDER BY
+func (o QueryOptions) isOrdered() bool {
+	return len(o.OrderBy) > 0 || o.Keyset
+}
+
+// SelectManyArgs - параметры SelectManyQuery: значения keyset-пагинации, если они заданы
+func SelectManyArgs(opts QueryOptions) []any {
+	if !opts.Keyset {
+		return nil
+	}
+	return opts.After
+}
+
+// KeysetAfter - значения PK записи last для QueryOptions.After следующей страницы
+func KeysetAfter[T any](info *dbs.StructInfo, last *T) ([]any, error) {
+	refs, err := info.PKFields().Refs(last)
+	if err != nil {
+		return nil, err
+	}
+	// Значения копируются, чтобы last можно было переиспользовать для чтения следующей страницы
+	for idx := range refs {
+		if rv := reflect.ValueOf(refs[idx]); rv.Kind() == reflect.Ptr {
+			refs[idx] = rv.Elem().Interface()
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// QuoteMode - режим экранирования идентификаторов в формируемых запросах
+type QuoteMode byte
+
+const (
+	// QuoteIfNeeded - экранировать только зарезервированные слова СУБД диалекта и имена,
+	// которые без экранирования будут прочитаны СУБД иначе (регистр, спецсимволы)
+	QuoteIfNeeded QuoteMode = iota
+	// QuoteAlways - экранировать все идентификаторы
+	QuoteAlways
+)
+
+var (
+	ErrInvalidAlias = errors.New("invalid alias")
+
+	// aliasRe - допустимый псевдоним таблицы: псевдонимы приходят извне и не экранируются по частям
+	aliasRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// lowerIdentRe - идентификатор, который СУБД со сворачиванием регистра в нижний (PostgreSQL) прочитает как есть
+	lowerIdentRe = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+	// plainIdentRe - идентификатор, не требующий экранирования в СУБД без сворачивания регистра
+	plainIdentRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+)
+
+func validateAlias(alias string) error {
+	if alias != "" && !aliasRe.MatchString(alias) {
+		return fmt.Errorf("%w [%s]", ErrInvalidAlias, alias)
+	}
+	return nil
+}
+
+// identByMode - идентификатор для подстановки в запрос с учётом режима экранирования;
+// reserved - зарезервированные слова СУБД диалекта
+func identByMode(
+	dialect Dialect, mode QuoteMode, plainRe *regexp.Regexp, reserved map[string]struct{}, name string,
+) string {
+	if mode == QuoteAlways || !plainRe.MatchString(name) || isKeyword(reserved, name) {
+		return dialect.QuoteIdent(name)
+	}
+	return name
+}
+
+func isKeyword(reserved map[string]struct{}, name string) bool {
+	_, found := reserved[strings.ToLower(name)]
+	return found
+}
+
+// writeTableName - имя таблицы, возможно, с указанием схемы через точку; каждая часть экранируется отдельно
+func writeTableName(writer io.StringWriter, dialect Dialect, tableName string) {
+	for idx, part := range strings.Split(tableName, ".") {
+		if idx > 0 {
+			_, _ = writer.WriteString(".")
+		}
+		_, _ = writer.WriteString(dialect.Ident(part))
+	}
+}
+
+// writeNames - список имён полей через запятую, каждое с префиксом alias, если он задан
+func writeNames(writer io.StringWriter, dialect Dialect, list dbs.FieldInfoList, alias string) {
+	for idx := range list {
+		if idx > 0 {
+			_, _ = writer.WriteString(", ")
+		}
+		if alias != "" {
+			_, _ = writer.WriteString(alias)
+			_, _ = writer.WriteString(".")
+		}
+		_, _ = writer.WriteString(dialect.Ident(list[idx].Name))
+	}
+}
@@ -0,0 +1,105 @@
+package adapters_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type User struct {
+	ID    int64 `dbs:"auto;pk"`
+	Group string
+	Title string `dbs:"name:Title"`
+}
+
+type SchemaRec struct {
+	ID int64 `dbs:"pk"`
+}
+
+func (SchemaRec) TableName() string {
+	return "archive.SchemaRec"
+}
+
+func TestIdent_QuoteIfNeeded(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(User{})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`INSERT INTO "user" ("group", "Title") VALUES ($1, $2) RETURNING id, "group", "Title"`,
+		(&adapters.PGAdapter{}).InsertOneQuery(si))
+	// СУБД без сворачивания регистра не требуют экранирования имён в смешанном регистре,
+	// а user в MySQL - не зарезервированное слово
+	assert.Equal(t,
+		"INSERT INTO user (`group`, Title) VALUES (?, ?)",
+		(&adapters.MySQLAdapter{}).InsertOneQuery(si))
+	assert.Equal(t,
+		"SELECT TOP (1) id, [group], Title FROM [user] WHERE id=@p1;",
+		(&adapters.MSSQLAdapter{}).SelectOneQuery(si))
+	assert.Equal(t,
+		`SELECT "order".id, "order"."group", "order"."Title" FROM "user" "order"`,
+		selectMany(t, (&adapters.PGAdapter{}), si, adapters.QueryOptions{WithAlias: "order"}))
+
+	si, err = dbs.NewStructInfo(SchemaRec{})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`SELECT id FROM archive."SchemaRec" WHERE id=$1 LIMIT 1;`,
+		(&adapters.PGAdapter{}).SelectOneQuery(si))
+}
+
+func TestIdent_ReservedWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dialect adapters.Dialect
+		words   []string
+	}{
+		{&adapters.PGAdapter{}, []string{"collation", "concurrently", "current_schema", "user", "order"}},
+		{&adapters.MySQLAdapter{}, []string{"rank", "groups", "system", "return", "double", "explain", "ignore"}},
+		{&adapters.MSSQLAdapter{}, []string{"file", "open", "public", "function", "merge", "begin", "rule"}},
+		{&adapters.SQLiteAdapter{}, []string{"index", "groups", "abort", "pragma", "vacuum"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			t.Parallel()
+			for _, word := range tt.words {
+				assert.Equal(t, tt.dialect.QuoteIdent(word), tt.dialect.Ident(word))
+				upper := strings.ToUpper(word)
+				assert.Equal(t, tt.dialect.QuoteIdent(upper), tt.dialect.Ident(upper), "case insensitive")
+			}
+			assert.Equal(t, "amount", tt.dialect.Ident("amount"))
+		})
+	}
+	assert.Equal(t, "rank", (&adapters.PGAdapter{}).Ident("rank"), "keywords of other dialects aren't quoted")
+}
+
+func TestIdent_QuoteAlways(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`DELETE FROM "test_rec" WHERE "id"=?1 RETURNING "id", "kind", "name", "aux_field"`,
+		adapters.NewSQLiteAdapter(adapters.SQLiteOptions{Quoting: adapters.QuoteAlways}).DeleteOneQuery(si))
+}
+
+func TestQueryOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, adapters.QueryOptions{WithAlias: "t1"}.Validate())
+	require.NoError(t, adapters.QueryOptions{}.Validate())
+
+	badOpts := adapters.QueryOptions{WithAlias: "t; DROP TABLE test_rec; --"}
+	require.ErrorIs(t, badOpts.Validate(), adapters.ErrInvalidAlias)
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+	_, err = (&adapters.PGAdapter{}).SelectManyQuery(si, badOpts)
+	require.ErrorIs(t, err, adapters.ErrInvalidAlias)
+}
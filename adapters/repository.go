@@ -0,0 +1,159 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+
+	"github.com/mirrorru/dbs"
+)
+
+// Repository - типизированный доступ к таблице структуры T через database/sql:
+// запросы диалекта, их параметры и приёмники связываются с выполнением запросов.
+// Записи ищутся по PK; отсутствие записи в Get и Delete, а также в Update для диалектов,
+// возвращающих значения из DML, сообщается ошибкой sql.ErrNoRows
+type Repository[T any] struct {
+	db      SQLQuerier
+	dialect Dialect
+	info    *dbs.StructInfo
+}
+
+// NewRepository - репозиторий записей T, выполняющий запросы диалекта dialect через db (*sql.DB, *sql.Tx, *sql.Conn)
+func NewRepository[T any](db SQLQuerier, dialect Dialect) (*Repository[T], error) {
+	var sample T
+	info, err := dbs.NewStructInfo(sample)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository[T]{db: db, dialect: dialect, info: info}, nil
+}
+
+// WithDB - копия репозитория, выполняющая запросы через db, например, через транзакцию *sql.Tx
+func (r *Repository[T]) WithDB(db SQLQuerier) *Repository[T] {
+	clone := *r
+	clone.db = db
+	return &clone
+}
+
+// DB - выполняющий запросы *sql.DB, *sql.Tx или *sql.Conn
+func (r *Repository[T]) DB() SQLQuerier {
+	return r.db
+}
+
+// Dialect - диалект, формирующий запросы
+func (r *Repository[T]) Dialect() Dialect {
+	return r.dialect
+}
+
+// Info - описание структуры T
+func (r *Repository[T]) Info() *dbs.StructInfo {
+	return r.info
+}
+
+// Insert - вставить запись item и заполнить её автогенерируемые поля
+func (r *Repository[T]) Insert(ctx context.Context, item *T) error {
+	if !r.dialect.CanReturnValuesInDML() {
+		return InsertOneByLastInsertID(ctx, r.db, r.dialect, r.info, item)
+	}
+	args, err := InsertOneArgs(r.info, item)
+	if err != nil {
+		return err
+	}
+	receivers, err := InsertOneReceivers(r.info, item)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, r.dialect.InsertOneQuery(r.info), args...).Scan(receivers...)
+}
+
+// Get - прочитать в dest запись с PK, заданным в dest
+func (r *Repository[T]) Get(ctx context.Context, dest *T) error {
+	args, err := SelectOneArgs(r.info, dest)
+	if err != nil {
+		return err
+	}
+	receivers, err := SelectOneReceivers(r.info, dest)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, r.dialect.SelectOneQuery(r.info), args...).Scan(receivers...)
+}
+
+// Update - обновить по PK поля записи item, не входящие в PK.
+// Для диалектов, не возвращающих значения из DML, отсутствие записи не обнаруживается: MySQL считает
+// только изменённые строки, и обновление записи теми же значениями неотличимо от отсутствия записи
+func (r *Repository[T]) Update(ctx context.Context, item *T) error {
+	args, err := UpdateOneArgs(r.info, item)
+	if err != nil {
+		return err
+	}
+	query := r.dialect.UpdateOneQuery(r.info)
+	if !r.dialect.CanReturnValuesInDML() {
+		_, err = r.db.ExecContext(ctx, query, args...)
+		return err
+	}
+	receivers, err := UpdateOneReceivers(r.info, item)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, query, args...).Scan(receivers...)
+}
+
+// Delete - удалить запись с PK, заданным в item. Для диалектов, возвращающих значения из DML,
+// item заполняется значениями удалённой записи, для остальных отсутствие записи определяется
+// по количеству удалённых строк
+func (r *Repository[T]) Delete(ctx context.Context, item *T) error {
+	args, err := DeleteOneArgs(r.info, item)
+	if err != nil {
+		return err
+	}
+	query := r.dialect.DeleteOneQuery(r.info)
+	if !r.dialect.CanReturnValuesInDML() {
+		result, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	}
+	receivers, err := DeleteOneReceivers(r.info, item)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, query, args...).Scan(receivers...)
+}
+
+// List - выбрать записи по условиям where (nil - все записи) с сортировкой и пагинацией из opts.
+// Возвращает записи и общее количество строк выборки (см. CollectMany)
+func (r *Repository[T]) List(ctx context.Context, opts QueryOptions, where *Criteria) ([]T, int64, error) {
+	query, args, err := SelectManyWhere(r.dialect, r.info, opts, where)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return CollectMany[T](rows, r.info, opts)
+}
+
+// Iter - построчное чтение записей, отобранных по условиям where (см. SelectManyIter)
+func (r *Repository[T]) Iter(ctx context.Context, opts QueryOptions, where *Criteria, reuse bool) iter.Seq2[*T, error] {
+	return SelectManyIter[T](ctx, r.db, r.dialect, r.info, opts, where, reuse)
+}
+
+// FromContext - копия репозитория, выполняющая запросы в транзакции WithTx из ctx, если она есть
+func (r *Repository[T]) FromContext(ctx context.Context) *Repository[T] {
+	if tx, ok := TxFromContext(ctx); ok {
+		return r.WithDB(tx)
+	}
+	return r
+}
@@ -0,0 +1,88 @@
+package adapters_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_PG(t *testing.T) {
+	t.Parallel()
+
+	stamp := time.Date(2025, 5, 6, 7, 8, 9, 0, time.UTC)
+	columns := []string{"id", "kind", "name", "aux_field"}
+	db, conn := openFakeDB(t,
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{{int64(11), int64(1), "one", stamp}}},
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{{int64(11), int64(1), "one", stamp}}},
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{{int64(11), int64(2), "two", stamp}}},
+		fakeResponse{Columns: columns},
+		fakeResponse{Columns: append(columns, "count"), Rows: [][]driver.Value{{int64(11), int64(2), "two", stamp, int64(7)}}},
+	)
+	repo, err := adapters.NewRepository[TestRec](db, &adapters.PGAdapter{})
+	require.NoError(t, err)
+
+	rec := TestRec{TestBody: TestBody{Kind: 1, Name: "one"}, AuxField: stamp}
+	require.NoError(t, repo.Insert(t.Context(), &rec))
+	assert.Equal(t, int64(11), rec.ID)
+
+	got := TestRec{TestKey: TestKey{ID: 11}}
+	require.NoError(t, repo.Get(t.Context(), &got))
+	assert.Equal(t, rec, got)
+
+	got.Kind, got.Name = 2, "two"
+	require.NoError(t, repo.Update(t.Context(), &got))
+
+	missing := TestRec{TestKey: TestKey{ID: 12}}
+	require.ErrorIs(t, repo.Delete(t.Context(), &missing), sql.ErrNoRows)
+
+	opts := adapters.QueryOptions{WithTotals: true, Limit: 1}
+	list, total, err := repo.List(t.Context(), opts, adapters.Where(repo.Info()).Eq("kind", 2))
+	require.NoError(t, err)
+	assert.Equal(t, []TestRec{got}, list)
+	assert.Equal(t, int64(7), total)
+
+	calls := conn.Calls()
+	require.Len(t, calls, 5)
+	assert.Equal(t, "INSERT INTO test_rec (kind, name, aux_field) VALUES ($1, $2, $3) RETURNING id, kind, name, aux_field",
+		calls[0].Query)
+	assert.Equal(t, "DELETE FROM test_rec WHERE id=$1 RETURNING id, kind, name, aux_field", calls[3].Query)
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field, COUNT(*) OVER() FROM test_rec WHERE kind=$1 LIMIT 1", calls[4].Query)
+}
+
+func TestRepository_MySQLTx(t *testing.T) {
+	t.Parallel()
+
+	db, conn := openFakeDB(t,
+		fakeResponse{LastInsertID: 21, RowsAffected: 1},
+		fakeResponse{RowsAffected: 1},
+		fakeResponse{RowsAffected: 1},
+		fakeResponse{RowsAffected: 0},
+	)
+	repo, err := adapters.NewRepository[TestRec](db, &adapters.MySQLAdapter{})
+	require.NoError(t, err)
+
+	tx, err := db.BeginTx(t.Context(), nil)
+	require.NoError(t, err)
+	txRepo := repo.WithDB(tx)
+	assert.Same(t, db, repo.DB())
+
+	rec := TestRec{TestBody: TestBody{Name: "one"}}
+	require.NoError(t, txRepo.Insert(t.Context(), &rec))
+	assert.Equal(t, int64(21), rec.ID)
+	require.NoError(t, txRepo.Update(t.Context(), &rec))
+	require.NoError(t, txRepo.Delete(t.Context(), &rec))
+	require.ErrorIs(t, txRepo.Delete(t.Context(), &rec), sql.ErrNoRows)
+	require.NoError(t, tx.Commit())
+
+	assert.Equal(t, []string{"BEGIN", "COMMIT"}, conn.TxLog())
+	calls := conn.Calls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, "UPDATE test_rec SET kind=?, name=?, aux_field=? WHERE id=?", calls[1].Query)
+	assert.Equal(t, "DELETE FROM test_rec WHERE id=?", calls[2].Query)
+}
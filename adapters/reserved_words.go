@@ -0,0 +1,95 @@
+package adapters
+
+import "strings"
+
+// keywordSet - множество ключевых слов из списка, разделённого пробелами
+func keywordSet(words string) map[string]struct{} {
+	fields := strings.Fields(words)
+	result := make(map[string]struct{}, len(fields))
+	for _, word := range fields {
+		result[word] = struct{}{}
+	}
+	return result
+}
+
+// pgReservedWords - слова PostgreSQL, недопустимые как имена полей и таблиц без экранирования:
+// "reserved" и "reserved (can be function or type)" из приложения "SQL Key Words" документации PostgreSQL
+var pgReservedWords = keywordSet(`
+	all analyse analyze and any array as asc asymmetric authorization binary both case cast check collate
+	collation column concurrently constraint create cross current_catalog current_date current_role
+	current_schema current_time current_timestamp current_user default deferrable desc distinct do else end
+	except false fetch for foreign freeze from full grant group having ilike in initially inner intersect into
+	is isnull join lateral leading left like limit localtime localtimestamp natural not notnull null offset on
+	only or order outer overlaps placing primary references returning right select session_user similar some
+	symmetric system_user table tablesample then to trailing true union unique user using variadic verbose
+	when where window with`)
+
+// mysqlReservedWords - зарезервированные слова MySQL 8 (отмеченные (R) в разделе "Keywords and Reserved
+// Words" документации MySQL), а также зарезервированные слова MariaDB
+var mysqlReservedWords = keywordSet(`
+	accessible add all alter analyze and as asc asensitive before between bigint binary blob both by call
+	cascade case change char character check collate column condition constraint continue convert create
+	cross cube cume_dist current_date current_role current_time current_timestamp current_user cursor database
+	databases day_hour day_microsecond day_minute day_second dec decimal declare default delayed delete
+	delete_domain_id dense_rank desc describe deterministic distinct distinctrow div do_domain_ids double
+	drop dual each else elseif empty enclosed escaped except exists exit explain false fetch first_value
+	float float4 float8 for force foreign from fulltext function general generated get grant group grouping
+	groups having high_priority hour_microsecond hour_minute hour_second if ignore ignore_domain_ids
+	ignore_server_ids in index infile inner inout insensitive insert int int1 int2 int3 int4 int8 integer
+	intersect interval into io_after_gtids io_before_gtids is iterate join json_table key keys kill lag
+	last_value lateral lead leading leave left like limit linear lines load localtime localtimestamp lock
+	long longblob longtext loop low_priority manual master_bind master_heartbeat_period
+	master_ssl_verify_server_cert match maxvalue mediumblob mediumint mediumtext middleint
+	minute_microsecond minute_second mod modifies natural not no_write_to_binlog nth_value ntile null
+	numeric of offset on optimize optimizer_costs option optionally or order out outer outfile over
+	page_checksum parallel parse_vcol_expr partition percent_rank position precision primary procedure
+	purge qualify range rank read read_write reads real recursive ref_system_id references regexp release
+	rename repeat replace require resignal restrict return returning revoke right rlike row row_number rows
+	schema schemas second_microsecond select sensitive separator set show signal slow smallint spatial
+	specific sql sql_big_result sql_calc_found_rows sql_small_result sqlexception sqlstate sqlwarning ssl
+	starting stats_auto_recalc stats_persistent stats_sample_pages stored straight_join system table
+	terminated then tinyblob tinyint tinytext to trailing trigger true undo union unique unlock unsigned
+	update usage use using utc_date utc_time utc_timestamp values varbinary varchar varcharacter varying
+	virtual when where while window with write xor year_month zerofill`)
+
+// mssqlReservedWords - зарезервированные слова Transact-SQL и ODBC из раздела "Reserved Keywords"
+// документации SQL Server
+var mssqlReservedWords = keywordSet(`
+	absolute action ada add all allocate alter and any are as asc assertion at authorization avg backup
+	begin between bit bit_length both break browse bulk by cascade cascaded case cast catalog char
+	char_length character character_length check checkpoint close clustered coalesce collate collation
+	column commit compute connect connection constraint constraints contains containstable continue convert
+	corresponding count create cross current current_date current_time current_timestamp current_user cursor
+	database date dbcc deallocate dec decimal declare default deferrable deferred delete deny desc describe
+	descriptor diagnostics disconnect disk distinct distributed domain double drop dump else end end-exec
+	errlvl escape except exception exec execute exists exit external extract false fetch file fillfactor
+	first float for foreign fortran found freetext freetexttable from full function get global go goto grant
+	group having holdlock hour identity identity_insert identitycol if immediate in include index indicator
+	initially inner input insensitive insert int integer intersect interval into is isolation join key kill
+	language last leading left level like lineno load local lower match max merge min minute module month
+	names national natural nchar next no nocheck nonclustered none not null nullif numeric octet_length of
+	off offsets on only open opendatasource openquery openrowset openxml option or order outer output over
+	overlaps pad partial pascal percent pivot plan position precision prepare preserve primary print prior
+	privileges proc procedure public raiserror read readtext real reconfigure references relative
+	replication restore restrict return revert revoke right rollback rows rowcount rowguidcol rule save
+	schema scroll second section securityaudit select semantickeyphrasetable
+	semanticsimilaritydetailstable semanticsimilaritytable session session_user set setuser shutdown size
+	smallint some space sql sqlca sqlcode sqlerror sqlstate sqlwarning statistics substring sum system_user
+	table tablesample temporary textsize then time timestamp timezone_hour timezone_minute to top trailing
+	tran transaction translate translation trigger trim true truncate try_convert tsequal union unique
+	unknown unpivot update updatetext upper usage use user using value values varchar varying view waitfor
+	when whenever where while with within work write writetext year zone`)
+
+// sqliteReservedWords - ключевые слова SQLite из раздела "SQL Keywords" документации SQLite.
+// Часть из них SQLite принимает и без экранирования, но их набор зависит от контекста и версии
+var sqliteReservedWords = keywordSet(`
+	abort action add after all alter always analyze and as asc attach autoincrement before begin between by
+	cascade case cast check collate column commit conflict constraint create cross current current_date
+	current_time current_timestamp database default deferrable deferred delete desc detach distinct do drop
+	each else end escape except exclude exclusive exists explain fail filter first following for foreign from
+	full generated glob group groups having if ignore immediate in index indexed initially inner insert
+	instead intersect into is isnull join key last left like limit match materialized natural no not nothing
+	notnull null nulls of offset on or order others outer over partition plan pragma preceding primary query
+	raise range recursive references regexp reindex release rename replace restrict returning right rollback
+	row rows savepoint select set table temp temporary then ties to transaction trigger unbounded union unique
+	update using vacuum values view virtual when where window with without`)
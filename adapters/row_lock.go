@@ -0,0 +1,124 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// LockStrength - режим блокировки выбранных строк
+type LockStrength byte
+
+const (
+	LockNone           LockStrength = iota // Без блокировки
+	LockForUpdate                          // FOR UPDATE
+	LockForNoKeyUpdate                     // FOR NO KEY UPDATE (только PostgreSQL)
+	LockForShare                           // FOR SHARE
+	LockForKeyShare                        // FOR KEY SHARE (только PostgreSQL)
+)
+
+// LockWaitPolicy - поведение при встрече строк, уже заблокированных другими транзакциями
+type LockWaitPolicy byte
+
+const (
+	LockWait       LockWaitPolicy = iota // Ждать снятия блокировки
+	LockNoWait                           // NOWAIT - сразу вернуть ошибку
+	LockSkipLocked                       // SKIP LOCKED - пропустить заблокированные строки
+)
+
+// RowLock - блокировка строк, выбранных запросом
+type RowLock struct {
+	Strength LockStrength
+	Wait     LockWaitPolicy
+}
+
+var (
+	errInvalidLock     = fmt.Errorf("%w: invalid row lock", ErrInvalidOptions)
+	errLockUnsupported = errors.New("row lock isn't supported by dialect")
+)
+
+// IsZero - блокировка не задана
+func (l RowLock) IsZero() bool {
+	return l == RowLock{}
+}
+
+// Validate - проверить допустимость сочетания режима блокировки и поведения при ожидании
+func (l RowLock) Validate() error {
+	switch {
+	case l.Strength > LockForKeyShare || l.Wait > LockSkipLocked:
+		return errInvalidLock
+	case l.Strength == LockNone && l.Wait != LockWait:
+		return fmt.Errorf("%w: wait policy without lock strength", errInvalidLock)
+	}
+	return nil
+}
+
+// variant - представление блокировки для ключа кэша
+func (l RowLock) variant() string {
+	return strconv.Itoa(int(l.Strength)) + "." + strconv.Itoa(int(l.Wait))
+}
+
+// lockClause - общая часть LockClause диалектов с блокировками FOR ...; withKeyLocks - поддержка
+// режимов NO KEY UPDATE и KEY SHARE
+func lockClause(dialect Dialect, lock RowLock, withKeyLocks bool) (string, error) {
+	if err := lock.Validate(); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	switch lock.Strength {
+	case LockNone:
+		return "", nil
+	case LockForUpdate:
+		_, _ = sb.WriteString(" FOR UPDATE")
+	case LockForShare:
+		_, _ = sb.WriteString(" FOR SHARE")
+	case LockForNoKeyUpdate, LockForKeyShare:
+		if !withKeyLocks {
+			return "", fmt.Errorf("%w [%s]: key lock", errLockUnsupported, dialect.Name())
+		}
+		if lock.Strength == LockForNoKeyUpdate {
+			_, _ = sb.WriteString(" FOR NO KEY UPDATE")
+		} else {
+			_, _ = sb.WriteString(" FOR KEY SHARE")
+		}
+	}
+	switch lock.Wait {
+	case LockNoWait:
+		_, _ = sb.WriteString(" NOWAIT")
+	case LockSkipLocked:
+		_, _ = sb.WriteString(" SKIP LOCKED")
+	}
+	return sb.String(), nil
+}
+
+// noLockClause - LockClause диалектов без блокировок строк в SELECT
+func noLockClause(dialect Dialect, lock RowLock) (string, error) {
+	if err := lock.Validate(); err != nil {
+		return "", err
+	}
+	if !lock.IsZero() {
+		return "", fmt.Errorf("%w [%s]", errLockUnsupported, dialect.Name())
+	}
+	return "", nil
+}
+
+// selectOneLockedQuery - общая часть SelectOneLockedQuery диалектов: блокировка дописывается
+// к SelectOneQuery и входит в ключ кэша
+func selectOneLockedQuery(dialect Dialect, info *dbs.StructInfo, lock RowLock) (string, error) {
+	clause, err := dialect.LockClause(lock)
+	if err != nil {
+		return "", err
+	}
+	base := dialect.SelectOneQuery(info)
+	if clause == "" {
+		return base, nil
+	}
+	key := makeCacheKey(info, queryKindSelectOne)
+	key.Variant = lock.variant()
+	return dialect.QueryCache().getOrPut(key, func() string {
+		return strings.TrimSuffix(base, ";") + clause + ";"
+	}), nil
+}
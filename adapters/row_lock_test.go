@@ -0,0 +1,79 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockClause(t *testing.T) {
+	t.Parallel()
+
+	pg, mysql := &adapters.PGAdapter{}, &adapters.MySQLAdapter{}
+
+	clause, err := pg.LockClause(adapters.RowLock{Strength: adapters.LockForUpdate, Wait: adapters.LockSkipLocked})
+	require.NoError(t, err)
+	assert.Equal(t, " FOR UPDATE SKIP LOCKED", clause)
+	clause, err = pg.LockClause(adapters.RowLock{Strength: adapters.LockForKeyShare, Wait: adapters.LockNoWait})
+	require.NoError(t, err)
+	assert.Equal(t, " FOR KEY SHARE NOWAIT", clause)
+	clause, err = mysql.LockClause(adapters.RowLock{Strength: adapters.LockForShare})
+	require.NoError(t, err)
+	assert.Equal(t, " FOR SHARE", clause)
+	clause, err = (&adapters.SQLiteAdapter{}).LockClause(adapters.RowLock{})
+	require.NoError(t, err)
+	assert.Empty(t, clause)
+
+	_, err = mysql.LockClause(adapters.RowLock{Strength: adapters.LockForNoKeyUpdate})
+	require.Error(t, err)
+	_, err = (&adapters.SQLiteAdapter{}).LockClause(adapters.RowLock{Strength: adapters.LockForUpdate})
+	require.Error(t, err)
+	_, err = (&adapters.MSSQLAdapter{}).LockClause(adapters.RowLock{Strength: adapters.LockForUpdate})
+	require.Error(t, err)
+	_, err = pg.LockClause(adapters.RowLock{Wait: adapters.LockSkipLocked})
+	require.ErrorIs(t, err, adapters.ErrInvalidOptions)
+}
+
+func TestSelectQueries_Locked(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	pg := &adapters.PGAdapter{}
+	query, err := pg.SelectOneLockedQuery(si, adapters.RowLock{Strength: adapters.LockForUpdate, Wait: adapters.LockNoWait})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, kind, name, aux_field FROM test_rec WHERE id=$1 LIMIT 1 FOR UPDATE NOWAIT;", query)
+	query, err = pg.SelectOneLockedQuery(si, adapters.RowLock{})
+	require.NoError(t, err)
+	assert.Equal(t, pg.SelectOneQuery(si), query)
+	_, err = (&adapters.SQLiteAdapter{}).SelectOneLockedQuery(si, adapters.RowLock{Strength: adapters.LockForShare})
+	require.Error(t, err)
+
+	opts := adapters.QueryOptions{
+		OrderBy: []adapters.SortSpec{adapters.Asc("kind")},
+		Limit:   5,
+		Lock:    adapters.RowLock{Strength: adapters.LockForUpdate, Wait: adapters.LockSkipLocked},
+	}
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field FROM test_rec ORDER BY kind LIMIT 5 FOR UPDATE SKIP LOCKED",
+		selectMany(t, pg, si, opts))
+	cached := pg.QueryCache().Len()
+	assert.Equal(t, "SELECT id, kind, name, aux_field FROM test_rec ORDER BY kind LIMIT 5",
+		selectMany(t, pg, si, adapters.QueryOptions{OrderBy: opts.OrderBy, Limit: 5}),
+		"lock is appended after the cached query")
+	assert.Equal(t, cached, pg.QueryCache().Len(), "locked and unlocked queries share the cache entry")
+
+	query, _, err = adapters.SelectManyWhere(pg, si, opts, adapters.Where(si).Eq("name", "x"))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field FROM test_rec WHERE name=$1 ORDER BY kind LIMIT 5 FOR UPDATE SKIP LOCKED",
+		query)
+	_, _, err = adapters.SelectManyWhere(&adapters.SQLiteAdapter{}, si, opts, nil)
+	require.Error(t, err)
+	_, err = (&adapters.SQLiteAdapter{}).SelectManyQuery(si, opts)
+	require.Error(t, err)
+}
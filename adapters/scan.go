@@ -0,0 +1,79 @@
+package adapters
+
+import (
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mirrorru/dbs"
+)
+
+// Rows - общий интерфейс *sql.Rows и pgx.Rows, достаточный для чтения результатов
+type Rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+var (
+	_ Rows = (*sql.Rows)(nil)
+	_ Rows = (pgx.Rows)(nil)
+)
+
+// SelectManyReceivers - приёмники строки SelectManyQuery. При opts.WithTotals последним приёмником
+// добавляется total - для значения COUNT(*) OVER() в слот, зарезервированном FieldInfoList.Refs
+func SelectManyReceivers[T any](info *dbs.StructInfo, dest *T, opts QueryOptions, total *int64) ([]any, error) {
+	receivers, err := info.AllFields().Refs(dest)
+	if err != nil {
+		return nil, err
+	}
+	if opts.WithTotals {
+		receivers = append(receivers, total)
+	}
+	return receivers, nil
+}
+
+// collectManyPrealloc - наибольшая ёмкость, резервируемая CollectMany заранее по opts.Limit:
+// большой Limit - лишь верхняя граница, и выборка может оказаться намного меньше
+const collectManyPrealloc = 1024
+
+// CollectMany - прочитать все строки результата SelectManyQuery.
+// Возвращает записи и общее количество строк выборки: при opts.WithTotals - значение COUNT(*) OVER()
+// (без учёта LIMIT/OFFSET), иначе - количество прочитанных строк. Закрытие rows остаётся за вызывающим
+func CollectMany[T any](rows Rows, info *dbs.StructInfo, opts QueryOptions) ([]T, int64, error) {
+	return collectMany(rows, opts, func(item *T) ([]any, error) {
+		return info.AllFields().Refs(item)
+	})
+}
+
+// collectMany - общая часть CollectMany и PGXRepository.List; refs - приёмники полей записи item,
+// к которым при opts.WithTotals добавляется приёмник общего количества строк
+func collectMany[T any](rows Rows, opts QueryOptions, refs func(item *T) ([]any, error)) ([]T, int64, error) {
+	var (
+		result []T
+		total  int64
+	)
+	if opts.Limit > 0 {
+		result = make([]T, 0, min(opts.Limit, collectManyPrealloc))
+	}
+	for rows.Next() {
+		var item T
+		receivers, err := refs(&item)
+		if err != nil {
+			return nil, 0, err
+		}
+		if opts.WithTotals {
+			receivers = append(receivers, &total)
+		}
+		if err = rows.Scan(receivers...); err != nil {
+			return nil, 0, err
+		}
+		result = append(result, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if !opts.WithTotals {
+		total = int64(len(result))
+	}
+	return result, total, nil
+}
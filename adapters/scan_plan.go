@@ -0,0 +1,156 @@
+package adapters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dot"
+)
+
+// UnknownColumns - поведение при колонках результата, которым нет соответствующего поля структуры
+type UnknownColumns byte
+
+const (
+	// RejectUnknownColumns - считать неизвестную или повторную колонку ошибкой
+	RejectUnknownColumns UnknownColumns = iota
+	// IgnoreUnknownColumns - пропускать значения неизвестных и повторных колонок
+	IgnoreUnknownColumns
+)
+
+var (
+	ErrUnknownColumn = errors.New("unknown column")
+
+	errDuplicateColumn = errors.New("duplicate column")
+
+	scanPlans = dot.SyncStore[scanPlanKey, *ScanPlan]{}
+)
+
+type scanPlanKey struct {
+	Type    reflect.Type
+	Columns string
+	Mode    UnknownColumns
+}
+
+// ScanPlan - соответствие колонок результата полям структуры по именам.
+// Позволяет читать результаты запросов с произвольным порядком колонок, в т.ч. SELECT *
+type ScanPlan struct {
+	structType reflect.Type
+	indexes    [][]int // Индексы полей по порядку колонок, nil - колонка пропускается
+	isSlice    []bool  // Поле - слайс, читается через pq.Array, как в FieldInfoList.Refs
+}
+
+// PlanScan - получить план чтения колонок columns в структуру info.
+// Планы кэшируются для каждого сочетания типа, набора колонок и режима
+func PlanScan(info *dbs.StructInfo, columns []string, mode UnknownColumns) (*ScanPlan, error) {
+	key := scanPlanKey{Type: info.Type(), Columns: strings.Join(columns, "\x00"), Mode: mode}
+	if plan, found := scanPlans.GetCurrent(key); found {
+		return plan, nil
+	}
+
+	plan := &ScanPlan{
+		structType: info.Type(),
+		indexes:    make([][]int, len(columns)),
+		isSlice:    make([]bool, len(columns)),
+	}
+	used := make(map[string]struct{}, len(columns))
+	for idx, column := range columns {
+		field, found := info.PeekField(column)
+		_, duplicate := used[column]
+		switch {
+		case found && !duplicate:
+			used[column] = struct{}{}
+			plan.indexes[idx] = field.Index()
+			plan.isSlice[idx] = field.Type.Kind() == reflect.Slice
+		case mode == IgnoreUnknownColumns:
+		case duplicate:
+			return nil, fmt.Errorf("%w [%s]", errDuplicateColumn, column)
+		default:
+			return nil, fmt.Errorf("%w [%s] for [%s]", ErrUnknownColumn, column, info.TableName())
+		}
+	}
+	scanPlans.Put(key, plan)
+	return plan, nil
+}
+
+// Receivers - приёмники значений колонок для записи dest. Значения пропускаемых колонок отбрасываются
+func (p *ScanPlan) Receivers(dest any) ([]any, error) {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Type() != p.structType {
+		return nil, fmt.Errorf("pointer to %s needed, got %T", p.structType, dest)
+	}
+	rv = rv.Elem()
+
+	result := make([]any, len(p.indexes))
+	for idx, index := range p.indexes {
+		switch {
+		case index == nil:
+			result[idx] = new(any)
+		case p.isSlice[idx]:
+			result[idx] = pq.Array(rv.FieldByIndex(index).Addr().Interface())
+		default:
+			result[idx] = rv.FieldByIndex(index).Addr().Interface()
+		}
+	}
+	return result, nil
+}
+
+// SQLColumns - имена колонок результата database/sql
+func SQLColumns(rows *sql.Rows) ([]string, error) {
+	return rows.Columns()
+}
+
+// PgxColumns - имена колонок результата pgx
+func PgxColumns(rows pgx.Rows) []string {
+	descriptions := rows.FieldDescriptions()
+	result := make([]string, len(descriptions))
+	for idx := range descriptions {
+		result[idx] = descriptions[idx].Name
+	}
+	return result
+}
+
+// CollectByColumns - прочитать все строки rows с колонками columns, сопоставляя колонки полям по именам
+func CollectByColumns[T any](
+	rows Rows, columns []string, info *dbs.StructInfo, mode UnknownColumns,
+) ([]T, error) {
+	plan, err := PlanScan(info, columns, mode)
+	if err != nil {
+		return nil, err
+	}
+	var result []T
+	for rows.Next() {
+		var item T
+		receivers, errRecv := plan.Receivers(&item)
+		if errRecv != nil {
+			return nil, errRecv
+		}
+		if err = rows.Scan(receivers...); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CollectSQLByName - прочитать все строки результата database/sql, сопоставляя колонки полям по именам
+func CollectSQLByName[T any](rows *sql.Rows, info *dbs.StructInfo, mode UnknownColumns) ([]T, error) {
+	columns, err := SQLColumns(rows)
+	if err != nil {
+		return nil, err
+	}
+	return CollectByColumns[T](rows, columns, info, mode)
+}
+
+// CollectPgxByName - прочитать все строки результата pgx, сопоставляя колонки полям по именам
+func CollectPgxByName[T any](rows pgx.Rows, info *dbs.StructInfo, mode UnknownColumns) ([]T, error) {
+	return CollectByColumns[T](rows, PgxColumns(rows), info, mode)
+}
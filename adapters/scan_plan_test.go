@@ -0,0 +1,75 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanScan(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	plan, err := adapters.PlanScan(si, []string{"name", "id"}, adapters.RejectUnknownColumns)
+	require.NoError(t, err)
+	again, err := adapters.PlanScan(si, []string{"name", "id"}, adapters.RejectUnknownColumns)
+	require.NoError(t, err)
+	assert.Same(t, plan, again)
+
+	var rec TestRec
+	receivers, err := plan.Receivers(&rec)
+	require.NoError(t, err)
+	assert.Equal(t, []any{&rec.Name, &rec.ID}, receivers)
+
+	_, err = plan.Receivers(&TestKey{})
+	require.Error(t, err)
+
+	_, err = adapters.PlanScan(si, []string{"id", "extra"}, adapters.RejectUnknownColumns)
+	require.ErrorIs(t, err, adapters.ErrUnknownColumn)
+	_, err = adapters.PlanScan(si, []string{"id", "id"}, adapters.RejectUnknownColumns)
+	require.Error(t, err)
+
+	plan, err = adapters.PlanScan(si, []string{"id", "extra", "id"}, adapters.IgnoreUnknownColumns)
+	require.NoError(t, err)
+	receivers, err = plan.Receivers(&rec)
+	require.NoError(t, err)
+	require.Len(t, receivers, 3)
+	assert.Equal(t, &rec.ID, receivers[0])
+	assert.IsType(t, new(any), receivers[1])
+	assert.IsType(t, new(any), receivers[2])
+}
+
+func TestCollectSQLByName(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	stamp := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	columns := []string{"aux_field", "extra", "name", "id", "kind"}
+	row := []driver.Value{stamp, "skip", "one", int64(1), int64(2)}
+	db, _ := openFakeDB(t,
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{row}},
+		fakeResponse{Columns: columns, Rows: [][]driver.Value{row}},
+	)
+
+	rows, err := db.QueryContext(t.Context(), "SELECT * FROM test_table")
+	require.NoError(t, err)
+	recs, err := adapters.CollectSQLByName[TestRec](rows, si, adapters.IgnoreUnknownColumns)
+	require.NoError(t, rows.Close())
+	require.NoError(t, err)
+	assert.Equal(t, []TestRec{{TestKey: TestKey{ID: 1}, TestBody: TestBody{Kind: 2, Name: "one"}, AuxField: stamp}}, recs)
+
+	rows, err = db.QueryContext(t.Context(), "SELECT * FROM test_table")
+	require.NoError(t, err)
+	_, err = adapters.CollectSQLByName[TestRec](rows, si, adapters.RejectUnknownColumns)
+	require.NoError(t, rows.Close())
+	require.ErrorIs(t, err, adapters.ErrUnknownColumn)
+}
@@ -0,0 +1,71 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectManyReceivers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rec   TestRec
+		total int64
+	)
+	si, err := dbs.NewStructInfo(rec)
+	require.NoError(t, err)
+
+	receivers, err := adapters.SelectManyReceivers(si, &rec, adapters.QueryOptions{WithTotals: true}, &total)
+	require.NoError(t, err)
+	assert.Equal(t, append(testRecAllRefs(&rec), &total), receivers)
+
+	receivers, err = adapters.SelectManyReceivers(si, &rec, adapters.QueryOptions{}, &total)
+	require.NoError(t, err)
+	assert.Equal(t, testRecAllRefs(&rec), receivers)
+}
+
+func TestCollectMany(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	stamp := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	db, _ := openFakeDB(t,
+		fakeResponse{
+			Columns: []string{"id", "kind", "name", "aux_field", "count"},
+			Rows: [][]driver.Value{
+				{int64(1), int64(1), "one", stamp, int64(42)},
+				{int64(2), int64(2), "two", stamp, int64(42)},
+			},
+		},
+		fakeResponse{Columns: []string{"id", "kind", "name", "aux_field"}, Rows: [][]driver.Value{
+			{int64(3), int64(3), "three", stamp},
+		}},
+	)
+	pg := &adapters.PGAdapter{}
+
+	opts := adapters.QueryOptions{WithTotals: true, Limit: 2}
+	rows, err := db.QueryContext(t.Context(), selectMany(t, pg, si, opts))
+	require.NoError(t, err)
+	recs, total, err := adapters.CollectMany[TestRec](rows, si, opts)
+	require.NoError(t, rows.Close())
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), total)
+	require.Len(t, recs, 2)
+	assert.Equal(t, "two", recs[1].Name)
+
+	rows, err = db.QueryContext(t.Context(), selectMany(t, pg, si, adapters.QueryOptions{}))
+	require.NoError(t, err)
+	recs, total, err = adapters.CollectMany[TestRec](rows, si, adapters.QueryOptions{})
+	require.NoError(t, rows.Close())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []TestRec{{TestKey: TestKey{ID: 3}, TestBody: TestBody{Kind: 3, Name: "three"}, AuxField: stamp}}, recs)
+}
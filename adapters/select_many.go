@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// selectManyQuery - общая часть SelectManyQuery диалектов: кэшируется запрос без LIMIT/OFFSET,
+// пагинация дописывается числами, чтобы разные страницы не размножали записи кэша, а за ней - блокировка
+func selectManyQuery(
+	dialect Dialect, cache *QueryCache, info *dbs.StructInfo, opts QueryOptions, countByAlias bool,
+) (string, error) {
+	if err := opts.ValidateFor(info); err != nil {
+		return "", err
+	}
+	lock, err := dialect.LockClause(opts.Lock)
+	if err != nil {
+		return "", err
+	}
+
+	key := makeCacheKey(info, queryKindSelectMany)
+	key.Variant = opts.variant()
+	query := cache.getOrPut(key, func() string {
+		return buildSelectMany(dialect, info, opts, countByAlias)
+	})
+	if opts.Limit > 0 || opts.Offset > 0 {
+		query += dialect.Paging(opts.Limit, opts.Offset, opts.isOrdered())
+	}
+	return query + lock, nil
+}
+
+// SelectManyWhere - выборка записей по условиям where с сортировкой и пагинацией из opts.
+// Условия должны быть построены для info, а их поля - указываться без псевдонима или с псевдонимом
+// opts.WithAlias. Возвращает запрос и его параметры: параметры условий, затем значения keyset-пагинации
+func SelectManyWhere(
+	dialect Dialect, info *dbs.StructInfo, opts QueryOptions, where *Criteria,
+) (string, []any, error) {
+	conds, args, err := aliasedCriteriaConditions(dialect, info, where, opts.WithAlias, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	if conds == "" {
+		query, err := dialect.SelectManyQuery(info, opts)
+		if err != nil {
+			return "", nil, err
+		}
+		return query, SelectManyArgs(opts), nil
+	}
+
+	// Опции проверяются здесь: кэшируемая часть запроса с условиями формируется без сортировки,
+	// пагинации и блокировки
+	if err = opts.ValidateFor(info); err != nil {
+		return "", nil, err
+	}
+	lock, err := dialect.LockClause(opts.Lock)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var alias string
+	if opts.WithAlias != "" {
+		alias = dialect.Ident(opts.WithAlias)
+	}
+	headOpts := QueryOptions{WithTotals: opts.WithTotals, WithAlias: opts.WithAlias}
+	head, err := dialect.SelectManyQuery(info, headOpts)
+	if err != nil {
+		return "", nil, err
+	}
+	var sb strings.Builder
+	_, _ = sb.WriteString(head)
+	_, _ = sb.WriteString(" WHERE ")
+	_, _ = sb.WriteString(conds)
+	if opts.Keyset && len(opts.After) > 0 {
+		_, _ = sb.WriteString(" AND ")
+		writeKeysetCondition(&sb, dialect, info.PKFields(), alias, len(args)+1)
+		args = append(args, opts.After...)
+	}
+	writeOrderBy(&sb, dialect, info, opts, alias)
+	if opts.Limit > 0 || opts.Offset > 0 {
+		_, _ = sb.WriteString(dialect.Paging(opts.Limit, opts.Offset, opts.isOrdered()))
+	}
+	_, _ = sb.WriteString(lock)
+
+	return sb.String(), args, nil
+}
+
+// writeOrderBy - ORDER BY по полям сортировки или, при keyset-пагинации, по PK
+func writeOrderBy(sb *strings.Builder, dialect Dialect, info *dbs.StructInfo, opts QueryOptions, alias string) {
+	if opts.Keyset {
+		_, _ = sb.WriteString(" ORDER BY ")
+		writeNames(sb, dialect, info.PKFields(), alias)
+		return
+	}
+	for idx, spec := range opts.OrderBy {
+		if idx == 0 {
+			_, _ = sb.WriteString(" ORDER BY ")
+		} else {
+			_, _ = sb.WriteString(", ")
+		}
+		if alias != "" {
+			_, _ = sb.WriteString(alias)
+			_, _ = sb.WriteString(".")
+		}
+		_, _ = sb.WriteString(dialect.Ident(spec.Field))
+		if spec.Desc {
+			_, _ = sb.WriteString(" DESC")
+		}
+	}
+}
+
+// TupleComparer - необязательный интерфейс диалекта: умеет ли СУБД сравнивать кортежи (a, b) > (x, y).
+// Диалекты без этого интерфейса считаются умеющими
+type TupleComparer interface {
+	CanCompareTuples() bool
+}
+
+// canCompareTuples - умеет ли диалект сравнивать кортежи (см. TupleComparer)
+func canCompareTuples(dialect Dialect) bool {
+	comparer, ok := dialect.(TupleComparer)
+	return !ok || comparer.CanCompareTuples()
+}
+
+// writeKeysetCondition - условие "PK больше заданного". Составной PK сравнивается кортежем,
+// а для диалектов без сравнения кортежей разворачивается в ((a>$1) OR (a=$1 AND b>$2))
+func writeKeysetCondition(sb *strings.Builder, dialect Dialect, pkFields dbs.FieldInfoList, alias string, startIdx int) {
+	field := func(idx int) {
+		if alias != "" {
+			_, _ = sb.WriteString(alias)
+			_, _ = sb.WriteString(".")
+		}
+		_, _ = sb.WriteString(dialect.Ident(pkFields[idx].Name))
+	}
+	if len(pkFields) == 1 {
+		field(0)
+		_, _ = sb.WriteString(">")
+		_, _ = sb.WriteString(dialect.Placeholder(startIdx))
+		return
+	}
+	if canCompareTuples(dialect) {
+		_, _ = sb.WriteString("(")
+		for idx := range pkFields {
+			if idx > 0 {
+				_, _ = sb.WriteString(", ")
+			}
+			field(idx)
+		}
+		_, _ = sb.WriteString(") > (")
+		writePlaceholders(sb, dialect, pkFields, startIdx, ", ")
+		_, _ = sb.WriteString(")")
+		return
+	}
+	_, _ = sb.WriteString("(")
+	for last := range pkFields {
+		if last > 0 {
+			_, _ = sb.WriteString(" OR ")
+		}
+		_, _ = sb.WriteString("(")
+		for idx := range last + 1 {
+			if idx > 0 {
+				_, _ = sb.WriteString(" AND ")
+			}
+			field(idx)
+			if idx < last {
+				_, _ = sb.WriteString("=")
+			} else {
+				_, _ = sb.WriteString(">")
+			}
+			_, _ = sb.WriteString(dialect.Placeholder(startIdx + idx))
+		}
+		_, _ = sb.WriteString(")")
+	}
+	_, _ = sb.WriteString(")")
+}
+
+// limitOffset - LIMIT/OFFSET; noLimit - значение LIMIT, означающее отсутствие ограничения,
+// для СУБД, не допускающих OFFSET без LIMIT
+func limitOffset(limit, offset int, noLimit string) string {
+	var sb strings.Builder
+	if limit > 0 {
+		_, _ = sb.WriteString(" LIMIT ")
+		_, _ = sb.WriteString(strconv.Itoa(limit))
+	} else if offset > 0 && noLimit != "" {
+		_, _ = sb.WriteString(" LIMIT ")
+		_, _ = sb.WriteString(noLimit)
+	}
+	if offset > 0 {
+		_, _ = sb.WriteString(" OFFSET ")
+		_, _ = sb.WriteString(strconv.Itoa(offset))
+	}
+	return sb.String()
+}
@@ -0,0 +1,189 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type PairKeyRec struct {
+	OwnerID int64 `dbs:"pk"`
+	ItemID  int64 `dbs:"pk"`
+	Amount  int64
+}
+
+func TestSelectManyQuery_OrderAndPaging(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	opts := adapters.QueryOptions{
+		WithAlias: "t",
+		OrderBy:   []adapters.SortSpec{adapters.Desc("kind"), adapters.Asc("name")},
+		Limit:     20,
+		Offset:    40,
+	}
+	pg := &adapters.PGAdapter{}
+	assert.Equal(t,
+		"SELECT t.id, t.kind, t.name, t.aux_field FROM test_rec t ORDER BY t.kind DESC, t.name LIMIT 20 OFFSET 40",
+		selectMany(t, pg, si, opts))
+	opts.Offset = 60
+	assert.Equal(t,
+		"SELECT t.id, t.kind, t.name, t.aux_field FROM test_rec t ORDER BY t.kind DESC, t.name LIMIT 20 OFFSET 60",
+		selectMany(t, pg, si, opts))
+	assert.Equal(t, 1, pg.QueryCache().Len(), "LIMIT/OFFSET must not multiply cache entries")
+
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field FROM test_rec LIMIT 18446744073709551615 OFFSET 5",
+		selectMany(t, (&adapters.MySQLAdapter{}), si, adapters.QueryOptions{Offset: 5}))
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field FROM test_rec ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+		selectMany(t, (&adapters.MSSQLAdapter{}), si, adapters.QueryOptions{Limit: 10}))
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field FROM test_rec ORDER BY name OFFSET 5 ROWS",
+		selectMany(t, (&adapters.MSSQLAdapter{}), si,
+			adapters.QueryOptions{OrderBy: []adapters.SortSpec{adapters.Asc("name")}, Offset: 5}))
+}
+
+func TestSelectManyQuery_Keyset(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(PairKeyRec{})
+	require.NoError(t, err)
+
+	first := adapters.QueryOptions{Keyset: true, Limit: 100}
+	assert.Equal(t,
+		"SELECT owner_id, item_id, amount FROM pair_key_rec ORDER BY owner_id, item_id LIMIT 100",
+		selectMany(t, (&adapters.PGAdapter{}), si, first))
+
+	last := PairKeyRec{OwnerID: 7, ItemID: 9}
+	after, err := adapters.KeysetAfter(si, &last)
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(7), int64(9)}, after)
+
+	next := adapters.QueryOptions{Keyset: true, Limit: 100, After: after}
+	assert.Equal(t,
+		"SELECT owner_id, item_id, amount FROM pair_key_rec"+
+			" WHERE (owner_id, item_id) > ($1, $2) ORDER BY owner_id, item_id LIMIT 100",
+		selectMany(t, (&adapters.PGAdapter{}), si, next))
+	assert.Equal(t, after, adapters.SelectManyArgs(next))
+	assert.Equal(t,
+		"SELECT owner_id, item_id, amount FROM pair_key_rec"+
+			" WHERE ((owner_id>@p1) OR (owner_id=@p1 AND item_id>@p2)) ORDER BY owner_id, item_id"+
+			" OFFSET 0 ROWS FETCH NEXT 100 ROWS ONLY",
+		selectMany(t, (&adapters.MSSQLAdapter{}), si, next))
+}
+
+// tuplelessPG - PostgreSQL, объявленный не умеющим сравнивать кортежи, как сторонний диалект
+type tuplelessPG struct {
+	*adapters.PGAdapter
+}
+
+func (tuplelessPG) CanCompareTuples() bool {
+	return false
+}
+
+func TestSelectManyWhere_TupleComparer(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(PairKeyRec{})
+	require.NoError(t, err)
+
+	opts := adapters.QueryOptions{Keyset: true, After: []any{int64(7), int64(9)}}
+	query, _, err := adapters.SelectManyWhere(tuplelessPG{&adapters.PGAdapter{}}, si, opts,
+		adapters.Where(si).Gt("amount", 0))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"SELECT owner_id, item_id, amount FROM pair_key_rec"+
+			" WHERE amount>$1 AND ((owner_id>$2) OR (owner_id=$2 AND item_id>$3)) ORDER BY owner_id, item_id",
+		query)
+}
+
+func TestSelectManyWhere(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	opts := adapters.QueryOptions{WithAlias: "t", Keyset: true, After: []any{int64(50)}, Limit: 10}
+	query, args, err := adapters.SelectManyWhere(&adapters.PGAdapter{}, si, opts,
+		adapters.Where(si).As("t").Eq("kind", 3))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"SELECT t.id, t.kind, t.name, t.aux_field FROM test_rec t WHERE t.kind=$1 AND t.id>$2 ORDER BY t.id LIMIT 10",
+		query)
+	assert.Equal(t, []any{3, int64(50)}, args)
+
+	query, args, err = adapters.SelectManyWhere(&adapters.PGAdapter{}, si, opts, nil)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"SELECT t.id, t.kind, t.name, t.aux_field FROM test_rec t WHERE t.id>$1 ORDER BY t.id LIMIT 10",
+		query)
+	assert.Equal(t, []any{int64(50)}, args)
+
+	authorInfo, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+	_, _, err = adapters.SelectManyWhere(&adapters.PGAdapter{}, si, adapters.QueryOptions{},
+		adapters.Where(authorInfo).Eq("name", "x"))
+	require.Error(t, err, "criteria for another structure")
+	_, _, err = adapters.SelectManyWhere(&adapters.PGAdapter{}, si, opts, adapters.Where(si).As("s").Eq("kind", 3))
+	require.Error(t, err, "alias other than WithAlias")
+	_, _, err = adapters.SelectManyWhere(&adapters.PGAdapter{}, si, adapters.QueryOptions{},
+		adapters.Where(si).As("t").Eq("kind", 3))
+	require.Error(t, err, "alias without WithAlias")
+	_, _, err = adapters.SelectManyWhere(&adapters.PGAdapter{}, si, adapters.QueryOptions{}, adapters.Where(nil))
+	require.Error(t, err, "nil struct info")
+}
+
+func TestQueryOptions_ValidateFor(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	require.NoError(t, adapters.QueryOptions{OrderBy: []adapters.SortSpec{adapters.Asc("kind")}}.ValidateFor(si))
+	require.ErrorIs(t,
+		adapters.QueryOptions{OrderBy: []adapters.SortSpec{adapters.Asc("kind; DROP")}}.ValidateFor(si),
+		adapters.ErrUnknownField)
+	require.ErrorIs(t, adapters.QueryOptions{Limit: -1}.ValidateFor(si), adapters.ErrInvalidOptions)
+	require.ErrorIs(t,
+		adapters.QueryOptions{Keyset: true, OrderBy: []adapters.SortSpec{adapters.Asc("kind")}}.ValidateFor(si),
+		adapters.ErrInvalidOptions)
+	require.ErrorIs(t,
+		adapters.QueryOptions{Keyset: true, After: []any{1, 2}}.ValidateFor(si),
+		adapters.ErrInvalidOptions)
+
+	_, _, err = adapters.SelectManyWhere(&adapters.PGAdapter{}, si, adapters.QueryOptions{Limit: -1}, nil)
+	require.ErrorIs(t, err, adapters.ErrInvalidOptions)
+
+	pg := &adapters.PGAdapter{}
+	_, err = pg.SelectManyQuery(si, adapters.QueryOptions{OrderBy: []adapters.SortSpec{adapters.Asc("missing")}})
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+	_, err = pg.SelectManyQuery(si, adapters.QueryOptions{Keyset: true, After: []any{1, 2}})
+	require.ErrorIs(t, err, adapters.ErrInvalidOptions)
+}
+
+func TestSelectManyQuery_VariantIsUnambiguous(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(commaRec{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	single := selectMany(t, pg, si, adapters.QueryOptions{OrderBy: []adapters.SortSpec{adapters.Asc("a,b")}})
+	pair := selectMany(t, pg, si, adapters.QueryOptions{OrderBy: []adapters.SortSpec{adapters.Asc("a"), adapters.Asc("b")}})
+	assert.NotEqual(t, single, pair)
+	assert.Contains(t, pair, "ORDER BY a, b")
+}
+
+// selectMany - SelectManyQuery для заведомо допустимых опций
+func selectMany(t *testing.T, dialect adapters.Dialect, info *dbs.StructInfo, opts adapters.QueryOptions) string {
+	t.Helper()
+	query, err := dialect.SelectManyQuery(info, opts)
+	require.NoError(t, err)
+	return query
+}
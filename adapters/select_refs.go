@@ -0,0 +1,221 @@
+package adapters
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dot"
+)
+
+// Псевдоним основной таблицы в SelectWithRefsQuery, присоединяемые таблицы получают псевдонимы t1, t2, ...
+const refsBaseAlias = "t0"
+
+// refJoin - таблица, присоединяемая по ссылочному полю (тег dbs:"ref")
+type refJoin struct {
+	alias  string            // Псевдоним присоединяемой таблицы
+	target *dbs.StructInfo   // Описание ссылаемой структуры
+	fields dbs.FieldInfoList // Поля внешнего ключа в основной структуре
+	index  []int             // Индекс ссылочного поля в основной структуре
+	isPtr  bool              // Ссылка указателем: структура создаётся, только если строка присоединилась
+	inner  bool              // Ссылка не может быть NULL - присоединяется INNER JOIN
+}
+
+var refJoinsMap = dot.SyncStore[reflect.Type, []refJoin]{}
+
+// refJoins - присоединяемые таблицы структуры info в порядке ссылочных полей
+func refJoins(info *dbs.StructInfo) []refJoin {
+	return refJoinsMap.GetOrPut(info.Type(), func() []refJoin {
+		var joins []refJoin
+		for _, fld := range info.AllFields() {
+			if fld.RefData == nil {
+				continue
+			}
+			index, isPtr := fld.Index(), fld.Type.Kind() == reflect.Ptr
+			if !isPtr {
+				// Поле внешнего ключа вложено в ссылочное поле-структуру: отбрасываем его индекс в ней
+				if refField, found := fld.RefData.StructInfo.PeekField(fld.RefData.FieldName); found {
+					index = index[:len(index)-len(refField.Index())]
+				}
+			}
+			if last := len(joins) - 1; last >= 0 && slices.Equal(joins[last].index, index) {
+				joins[last].fields = append(joins[last].fields, fld)
+				joins[last].inner = joins[last].inner && !fld.IsNullable
+				continue
+			}
+			joins = append(joins, refJoin{
+				alias:  "t" + strconv.Itoa(len(joins)+1),
+				target: fld.RefData.StructInfo,
+				fields: dbs.FieldInfoList{fld},
+				index:  index,
+				isPtr:  isPtr,
+				inner:  !fld.IsNullable,
+			})
+		}
+		return joins
+	})
+}
+
+// buildSelectWithRefs - выборка записей вместе с записями, на которые ссылаются их ссылочные поля.
+// Ссылки, допускающие NULL, присоединяются LEFT JOIN, остальные - INNER JOIN
+func buildSelectWithRefs(dialect Dialect, info *dbs.StructInfo) string {
+	var sb strings.Builder
+
+	joins := refJoins(info)
+	allFields := info.AllFields()
+	sb.Grow(30 + (len(allFields)+len(joins)*4)*2*DefaultFieldNameLength)
+
+	_, _ = sb.WriteString("SELECT ")
+	writeNames(&sb, dialect, allFields, refsBaseAlias)
+	for idx := range joins {
+		_, _ = sb.WriteString(", ")
+		writeNames(&sb, dialect, joins[idx].target.AllFields(), joins[idx].alias)
+	}
+	_, _ = sb.WriteString(" FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" " + refsBaseAlias)
+	for idx := range joins {
+		join := &joins[idx]
+		if join.inner {
+			_, _ = sb.WriteString(" INNER JOIN ")
+		} else {
+			_, _ = sb.WriteString(" LEFT JOIN ")
+		}
+		writeTableName(&sb, dialect, join.target.TableName())
+		_, _ = sb.WriteString(" ")
+		_, _ = sb.WriteString(join.alias)
+		_, _ = sb.WriteString(" ON ")
+		for fldIdx := range join.fields {
+			if fldIdx > 0 {
+				_, _ = sb.WriteString(" AND ")
+			}
+			_, _ = sb.WriteString(join.alias)
+			_, _ = sb.WriteString(".")
+			_, _ = sb.WriteString(dialect.Ident(join.fields[fldIdx].RefData.FieldName))
+			_, _ = sb.WriteString("=" + refsBaseAlias + ".")
+			_, _ = sb.WriteString(dialect.Ident(join.fields[fldIdx].Name))
+		}
+	}
+
+	return sb.String()
+}
+
+// SelectWithRefsReceivers - приёмники строки SelectWithRefsQuery для записи dest (указатель на структуру info).
+// Значения присоединённых таблиц читаются во временные приёмники и переносятся в dest вызовом complete
+// после успешного Scan: ссылки-указатели заполняются, только если строка присоединилась, иначе обнуляются
+func SelectWithRefsReceivers(info *dbs.StructInfo, dest any) (receivers []any, complete func(), err error) {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Type() != info.Type() {
+		return nil, nil, fmt.Errorf("pointer to %s needed, got %T", info.Type(), dest)
+	}
+	rv = rv.Elem()
+
+	joins := refJoins(info)
+	allFields := info.AllFields()
+	receivers = make([]any, 0, len(allFields)+len(joins)*4)
+	for idx := range allFields {
+		fld := &allFields[idx]
+		if fld.RefData != nil && fld.Type.Kind() == reflect.Ptr {
+			// Ссылка-указатель заполняется из присоединённой строки
+			receivers = append(receivers, new(any))
+			continue
+		}
+		receivers = append(receivers, fieldReceiver(rv.FieldByIndex(fld.Index())))
+	}
+
+	holders := make([][]reflect.Value, len(joins))
+	for idx := range joins {
+		targetFields := joins[idx].target.AllFields()
+		holders[idx] = make([]reflect.Value, len(targetFields))
+		for fldIdx := range targetFields {
+			// Приёмник **T допускает NULL несовпавшей строки LEFT JOIN, слайсы читаются через pq.Array
+			var holder reflect.Value
+			if targetFields[fldIdx].Type.Kind() == reflect.Slice {
+				holder = reflect.New(targetFields[fldIdx].Type)
+				receivers = append(receivers, pq.Array(holder.Interface()))
+			} else {
+				holder = reflect.New(reflect.PointerTo(targetFields[fldIdx].Type))
+				receivers = append(receivers, holder.Interface())
+			}
+			holders[idx][fldIdx] = holder
+		}
+	}
+
+	complete = func() {
+		for idx := range joins {
+			completeRefJoin(rv, &joins[idx], holders[idx])
+		}
+	}
+	return receivers, complete, nil
+}
+
+// completeRefJoin - перенести значения присоединённой строки в ссылочное поле записи parent
+func completeRefJoin(parent reflect.Value, join *refJoin, holders []reflect.Value) {
+	targetFields := join.target.AllFields()
+	found := false
+	for idx := range targetFields {
+		if targetFields[idx].IsPK && !holders[idx].Elem().IsNil() {
+			found = true
+			break
+		}
+	}
+
+	refField := parent.FieldByIndex(join.index)
+	if !found {
+		if join.isPtr {
+			refField.SetZero()
+		}
+		return
+	}
+	target := refField
+	if join.isPtr {
+		target = reflect.New(join.target.Type()).Elem()
+	}
+	for idx := range targetFields {
+		holder := holders[idx].Elem()
+		if holder.Kind() == reflect.Ptr {
+			if holder.IsNil() {
+				continue
+			}
+			holder = holder.Elem()
+		}
+		target.FieldByIndex(targetFields[idx].Index()).Set(holder)
+	}
+	if join.isPtr {
+		refField.Set(target.Addr())
+	}
+}
+
+// fieldReceiver - приёмник значения поля, слайсы читаются через pq.Array, как в FieldInfoList.Refs
+func fieldReceiver(field reflect.Value) any {
+	if field.Kind() == reflect.Slice {
+		return pq.Array(field.Addr().Interface())
+	}
+	return field.Addr().Interface()
+}
+
+// CollectWithRefs - прочитать все строки результата SelectWithRefsQuery вместе со ссылаемыми записями.
+// Закрытие rows остаётся за вызывающим
+func CollectWithRefs[T any](rows Rows, info *dbs.StructInfo) ([]T, error) {
+	var result []T
+	for rows.Next() {
+		var item T
+		receivers, complete, err := SelectWithRefsReceivers(info, &item)
+		if err != nil {
+			return nil, err
+		}
+		if err = rows.Scan(receivers...); err != nil {
+			return nil, err
+		}
+		complete()
+		result = append(result, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
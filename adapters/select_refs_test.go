@@ -0,0 +1,94 @@
+package adapters_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Author struct {
+	ID   int64 `dbs:"auto;pk"`
+	Name string
+	Tags []string
+}
+
+func (Author) TableName() string {
+	return "authors"
+}
+
+type Book struct {
+	ID     int64 `dbs:"auto;pk"`
+	Title  string
+	Author *Author `dbs:"ref"`
+	Editor Author  `dbs:"ref"`
+}
+
+func (Book) TableName() string {
+	return "books"
+}
+
+func TestSelectWithRefsQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Book{})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"SELECT t0.id, t0.title, t0.author_id, t0.editor_id, t1.id, t1.name, t1.tags, t2.id, t2.name, t2.tags"+
+			" FROM books t0 LEFT JOIN authors t1 ON t1.id=t0.author_id INNER JOIN authors t2 ON t2.id=t0.editor_id",
+		(&adapters.PGAdapter{}).SelectWithRefsQuery(si))
+	assert.Equal(t,
+		"SELECT t0.[id], t0.[title], t0.[author_id], t0.[editor_id], t1.[id], t1.[name], t1.[tags],"+
+			" t2.[id], t2.[name], t2.[tags] FROM [books] t0 LEFT JOIN [authors] t1 ON t1.[id]=t0.[author_id]"+
+			" INNER JOIN [authors] t2 ON t2.[id]=t0.[editor_id]",
+		adapters.NewMSSQLAdapter(adapters.QuoteAlways).SelectWithRefsQuery(si))
+
+	si, err = dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT t0.id, t0.kind, t0.name, t0.aux_field FROM test_rec t0",
+		(&adapters.PGAdapter{}).SelectWithRefsQuery(si))
+}
+
+func TestCollectWithRefs(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Book{})
+	require.NoError(t, err)
+
+	pg := &adapters.PGAdapter{}
+	db, _ := openFakeDB(t, fakeResponse{
+		Columns: []string{"id", "title", "author_id", "editor_id", "id", "name", "tags", "id", "name", "tags"},
+		Rows: [][]driver.Value{
+			{int64(1), "first", int64(10), int64(20), int64(10), "Ann", "{a,b}", int64(20), "Ed", nil},
+			{int64(2), "second", nil, int64(20), nil, nil, nil, int64(20), "Ed", "{c}"},
+		},
+	})
+
+	rows, err := db.QueryContext(t.Context(), pg.SelectWithRefsQuery(si))
+	require.NoError(t, err)
+	books, err := adapters.CollectWithRefs[Book](rows, si)
+	require.NoError(t, rows.Close())
+	require.NoError(t, err)
+	assert.Equal(t, []Book{
+		{
+			ID: 1, Title: "first",
+			Author: &Author{ID: 10, Name: "Ann", Tags: []string{"a", "b"}},
+			Editor: Author{ID: 20, Name: "Ed"},
+		},
+		{ID: 2, Title: "second", Editor: Author{ID: 20, Name: "Ed", Tags: []string{"c"}}},
+	}, books)
+
+	book := Book{Author: &Author{ID: 99}}
+	_, _, err = adapters.SelectWithRefsReceivers(si, book)
+	require.Error(t, err)
+	receivers, complete, err := adapters.SelectWithRefsReceivers(si, &book)
+	require.NoError(t, err)
+	require.Len(t, receivers, 10)
+	assert.Equal(t, &book.Editor.ID, receivers[3])
+	complete()
+	assert.Nil(t, book.Author, "unmatched pointer reference must be reset")
+}
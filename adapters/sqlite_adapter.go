@@ -0,0 +1,165 @@
+package adapters
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// SQLiteAdapter - диалект SQLite.
+// RETURNING поддерживается начиная с SQLite 3.35, для более старых версий следует включить
+// SQLiteOptions.WithoutReturning: тогда автогенерируемые поля после вставки заполняются через InsertOneByLastInsertID.
+// Настройки задаются конструктором NewSQLiteAdapter и не меняются, методы объявлены у указателя (см. PGAdapter)
+type SQLiteAdapter struct {
+	cache QueryCache
+
+	quoting          QuoteMode // Режим экранирования идентификаторов
+	withoutReturning bool      // Не использовать RETURNING в INSERT/UPDATE/DELETE
+}
+
+var _ Dialect = (*SQLiteAdapter)(nil)
+
+// SQLiteOptions - настройки диалекта SQLite
+type SQLiteOptions struct {
+	Quoting          QuoteMode // Режим экранирования идентификаторов
+	WithoutReturning bool      // Не использовать RETURNING в INSERT/UPDATE/DELETE (SQLite до 3.35)
+}
+
+// NewSQLiteAdapter - диалект SQLite с настройками opts
+func NewSQLiteAdapter(opts SQLiteOptions) *SQLiteAdapter {
+	return &SQLiteAdapter{quoting: opts.Quoting, withoutReturning: opts.WithoutReturning}
+}
+
+func (*SQLiteAdapter) Name() string {
+	return "sqlite"
+}
+
+func (*SQLiteAdapter) Placeholder(idx int) string {
+	return "?" + strconv.Itoa(idx)
+}
+
+func (*SQLiteAdapter) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (a *SQLiteAdapter) Ident(name string) string {
+	return identByMode(a, a.quoting, plainIdentRe, sqliteReservedWords, name)
+}
+
+func (a *SQLiteAdapter) CanReturnValuesInDML() bool {
+	return !a.withoutReturning
+}
+
+func (a *SQLiteAdapter) QueryCache() *QueryCache {
+	return &a.cache
+}
+
+func (*SQLiteAdapter) Paging(limit, offset int, _ bool) string {
+	return limitOffset(limit, offset, "-1")
+}
+
+// LockClause - SQLite блокирует базу целиком и не поддерживает блокировку строк
+func (a *SQLiteAdapter) LockClause(lock RowLock) (string, error) {
+	return noLockClause(a, lock)
+}
+
+func (a *SQLiteAdapter) InsertOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindInsertOne), func() string {
+		return buildInsertOne(a, info, a.CanReturnValuesInDML())
+	})
+}
+
+func (a *SQLiteAdapter) SelectOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectOne), func() string {
+		return buildSelectOne(a, info)
+	})
+}
+
+func (a *SQLiteAdapter) SelectOneLockedQuery(info *dbs.StructInfo, lock RowLock) (string, error) {
+	return selectOneLockedQuery(a, info, lock)
+}
+
+func (a *SQLiteAdapter) SelectManyQuery(info *dbs.StructInfo, opts QueryOptions) (string, error) {
+	return selectManyQuery(a, &a.cache, info, opts, false)
+}
+
+func (a *SQLiteAdapter) SelectWithRefsQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindSelectWithRefs), func() string {
+		return buildSelectWithRefs(a, info)
+	})
+}
+
+func (a *SQLiteAdapter) UpdateOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindUpdateOne), func() string {
+		return buildUpdateOne(a, info, a.CanReturnValuesInDML())
+	})
+}
+
+func (a *SQLiteAdapter) UpdateFieldsQuery(info *dbs.StructInfo, fieldNames ...string) (string, error) {
+	setFields, err := updateFieldsList(info, fieldNames)
+	if err != nil {
+		return "", err
+	}
+	return a.cache.getOrPut(makeUpdateFieldsKey(info, fieldNames), func() string {
+		return buildUpdateFields(a, info, setFields, a.CanReturnValuesInDML())
+	}), nil
+}
+
+func (a *SQLiteAdapter) DeleteOneQuery(info *dbs.StructInfo) string {
+	return a.cache.getOrPut(makeCacheKey(info, queryKindDeleteOne), func() string {
+		return buildDeleteOne(a, info, a.CanReturnValuesInDML())
+	})
+}
+
+func (a *SQLiteAdapter) SelectByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindSelectByPKs, keyCount), func() string {
+		return buildSelectByPKs(a, info, keyCount)
+	})
+}
+
+func (a *SQLiteAdapter) DeleteByPKsQuery(info *dbs.StructInfo, keyCount int) string {
+	return a.cache.getOrPut(makeByPKsKey(a, info, queryKindDeleteByPKs, keyCount), func() string {
+		return buildDeleteByPKs(a, info, keyCount, a.CanReturnValuesInDML())
+	})
+}
+
+func (a *SQLiteAdapter) UpdateWhereQuery(
+	info *dbs.StructInfo, where *Criteria, withReturning bool, fieldNames ...string,
+) (string, []any, error) {
+	setFields, conds, args, err := prepareUpdateWhere(a, info, where, withReturning, fieldNames)
+	if err != nil {
+		return "", nil, err
+	}
+	return buildUpdateWhere(a, info, setFields, conds, withReturning), args, nil
+}
+
+func (a *SQLiteAdapter) DeleteWhereQuery(info *dbs.StructInfo, where *Criteria, withReturning bool) (string, []any, error) {
+	conds, args, err := whereConditions(a, info, where, 0, withReturning)
+	if err != nil {
+		return "", nil, err
+	}
+	return buildDeleteWhere(a, info, conds, withReturning), args, nil
+}
+
+func (a *SQLiteAdapter) CountQuery(info *dbs.StructInfo, where *Criteria, mode CountMode) (string, []any, error) {
+	return countQuery(a, info, where, mode)
+}
+
+func (a *SQLiteAdapter) ExistsByPKQuery(info *dbs.StructInfo) string {
+	return existsByPKQuery(a, info)
+}
+
+func (a *SQLiteAdapter) ExistsWhereQuery(info *dbs.StructInfo, where *Criteria) (string, []any, error) {
+	return existsWhereQuery(a, info, where)
+}
+
+func (a *SQLiteAdapter) InsertManyQuery(info *dbs.StructInfo, rowCount int) (string, error) {
+	return insertManyQuery(a, info, rowCount, func() string {
+		return buildInsertMany(a, info, rowCount, a.CanReturnValuesInDML())
+	})
+}
+
+func (*SQLiteAdapter) InsertManyBatchSize(info *dbs.StructInfo) int {
+	return batchSizeByParams(info, sqliteMaxParams, 0)
+}
@@ -0,0 +1,56 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteAdapter_Queries(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	var dialect adapters.Dialect = &adapters.SQLiteAdapter{}
+	assert.True(t, dialect.CanReturnValuesInDML())
+	assert.Equal(t, "?3", dialect.Placeholder(3))
+	assert.Equal(t, `"Order"`, dialect.QuoteIdent("Order"))
+	assert.Equal(t,
+		"INSERT INTO test_rec (kind, name, aux_field) VALUES (?1, ?2, ?3) RETURNING id, kind, name, aux_field",
+		dialect.InsertOneQuery(si))
+	assert.Equal(t,
+		"SELECT id, kind, name, aux_field FROM test_rec WHERE id=?1 LIMIT 1;",
+		dialect.SelectOneQuery(si))
+	assert.Equal(t,
+		"SELECT t.id, t.kind, t.name, t.aux_field, COUNT(*) OVER() FROM test_rec t",
+		selectMany(t, dialect, si, adapters.QueryOptions{WithTotals: true, WithAlias: "t"}))
+	assert.Equal(t,
+		"UPDATE test_rec SET kind=?1, name=?2, aux_field=?3 WHERE id=?4 RETURNING id, kind, name, aux_field",
+		dialect.UpdateOneQuery(si))
+	assert.Equal(t,
+		"DELETE FROM test_rec WHERE id=?1 RETURNING id, kind, name, aux_field",
+		dialect.DeleteOneQuery(si))
+}
+
+func TestSQLiteAdapter_WithoutReturning(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	var dialect adapters.Dialect = adapters.NewSQLiteAdapter(adapters.SQLiteOptions{WithoutReturning: true})
+	assert.False(t, dialect.CanReturnValuesInDML())
+	assert.Equal(t,
+		"INSERT INTO test_rec (kind, name, aux_field) VALUES (?1, ?2, ?3)",
+		dialect.InsertOneQuery(si))
+	assert.Equal(t,
+		"UPDATE test_rec SET kind=?1, name=?2, aux_field=?3 WHERE id=?4",
+		dialect.UpdateOneQuery(si))
+	assert.Equal(t,
+		"DELETE FROM test_rec WHERE id=?1",
+		dialect.DeleteOneQuery(si))
+}
@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/mirrorru/dbs"
+)
+
+var errStreamTotals = fmt.Errorf("%w: totals can't be streamed", ErrInvalidOptions)
+
+// IterRows - построчное чтение результата запроса query, возвращающего поля AllFields структуры T.
+// Запрос выполняется при начале перебора; строки закрываются по его окончании, в том числе при выходе
+// из цикла досрочно. Ошибка выполнения, чтения или отмены ctx передаётся последним элементом перебора.
+// При reuse все строки читаются в одну и ту же запись с одними и теми же приёмниками: указатель
+// действителен только до следующего шага, сохранять нужно копию записи
+func IterRows[T any](
+	ctx context.Context, db SQLQuerier, info *dbs.StructInfo, reuse bool, query string, args ...any,
+) iter.Seq2[*T, error] {
+	return iterRows(ctx, db, &rowItem[T]{info: info, reuse: reuse}, query, args...)
+}
+
+// rowItem - запись, в которую читается очередная строка, и приёмники её полей.
+// При reuse запись и приёмники создаются один раз и переиспользуются для всех строк
+type rowItem[T any] struct {
+	info      *dbs.StructInfo
+	reuse     bool
+	item      *T
+	receivers []any
+}
+
+// next - подготовить запись для чтения очередной строки
+func (r *rowItem[T]) next() error {
+	if r.item != nil && r.reuse {
+		var zero T
+		*r.item = zero
+		return nil
+	}
+	r.item = new(T)
+	var err error
+	r.receivers, err = r.info.AllFields().Refs(r.item)
+	return err
+}
+
+// iterRows - IterRows с записью row, которая может переиспользоваться и между несколькими запросами
+func iterRows[T any](
+	ctx context.Context, db SQLQuerier, row *rowItem[T], query string, args ...any,
+) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			if err = ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			if err = row.next(); err != nil {
+				yield(nil, err)
+				return
+			}
+			if err = rows.Scan(row.receivers...); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(row.item, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// IterValues - построчное чтение результата запроса query, как IterRows, но записи передаются по значению,
+// поэтому приёмники переиспользуются всегда
+func IterValues[T any](
+	ctx context.Context, db SQLQuerier, info *dbs.StructInfo, query string, args ...any,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for item, err := range IterRows[T](ctx, db, info, true, query, args...) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(*item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// SelectManyIter - построчное чтение записей, отобранных по условиям where с сортировкой и пагинацией
+// из opts (см. SelectManyWhere и IterRows). Общее количество строк при потоковом чтении не возвращается,
+// opts.WithTotals - ошибка
+func SelectManyIter[T any](
+	ctx context.Context, db SQLQuerier, dialect Dialect, info *dbs.StructInfo, opts QueryOptions, where *Criteria,
+	reuse bool,
+) iter.Seq2[*T, error] {
+	if opts.WithTotals {
+		return func(yield func(*T, error) bool) { yield(nil, errStreamTotals) }
+	}
+	query, args, err := SelectManyWhere(dialect, info, opts, where)
+	if err != nil {
+		return func(yield func(*T, error) bool) { yield(nil, err) }
+	}
+	return IterRows[T](ctx, db, info, reuse, query, args...)
+}
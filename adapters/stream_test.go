@@ -0,0 +1,127 @@
+package adapters_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamRows() []fakeResponse {
+	return []fakeResponse{{
+		Columns: []string{"id", "name", "tags"},
+		Rows: [][]driver.Value{
+			{int64(1), "one", "{a}"},
+			{int64(2), "two", "{b,c}"},
+			{int64(3), "three", "{}"},
+		},
+	}}
+}
+
+func TestIterRows(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+	query := selectMany(t, (&adapters.PGAdapter{}), si, adapters.QueryOptions{})
+
+	db, _ := openFakeDB(t, streamRows()...)
+	var fresh []*Author
+	for item, err := range adapters.IterRows[Author](t.Context(), db, si, false, query) {
+		require.NoError(t, err)
+		fresh = append(fresh, item)
+	}
+	require.Len(t, fresh, 3)
+	assert.NotSame(t, fresh[0], fresh[1])
+	assert.Equal(t, Author{ID: 2, Name: "two", Tags: []string{"b", "c"}}, *fresh[1])
+
+	db, _ = openFakeDB(t, streamRows()...)
+	var (
+		reused []*Author
+		names  []string
+	)
+	for item, err := range adapters.IterRows[Author](t.Context(), db, si, true, query) {
+		require.NoError(t, err)
+		reused = append(reused, item)
+		names = append(names, item.Name)
+	}
+	require.Len(t, reused, 3)
+	assert.Same(t, reused[0], reused[2])
+	assert.Equal(t, []string{"one", "two", "three"}, names)
+	assert.Empty(t, reused[2].Tags)
+}
+
+func TestIterValues_Break(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+
+	db, conn := openFakeDB(t, streamRows()...)
+	var got []Author
+	for item, err := range adapters.IterValues[Author](t.Context(), db, si, "SELECT id, name, tags FROM authors") {
+		require.NoError(t, err)
+		got = append(got, item)
+		if len(got) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []Author{{ID: 1, Name: "one", Tags: []string{"a"}}, {ID: 2, Name: "two", Tags: []string{"b", "c"}}},
+		got)
+	assert.Zero(t, db.Stats().InUse, "rows are closed on break")
+	assert.Len(t, conn.Calls(), 1)
+}
+
+func TestIterRows_Context(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(Author{})
+	require.NoError(t, err)
+
+	db, _ := openFakeDB(t, streamRows()...)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	var (
+		count   int
+		lastErr error
+	)
+	for item, err := range adapters.IterRows[Author](ctx, db, si, false, "SELECT id, name, tags FROM authors") {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		require.NotNil(t, item)
+		count++
+		cancel()
+	}
+	assert.Equal(t, 1, count)
+	require.ErrorIs(t, lastErr, context.Canceled)
+	assert.Zero(t, db.Stats().InUse)
+}
+
+func TestSelectManyIter(t *testing.T) {
+	t.Parallel()
+
+	db, conn := openFakeDB(t, streamRows()...)
+	repo, err := adapters.NewRepository[Author](db, &adapters.PGAdapter{})
+	require.NoError(t, err)
+
+	var ids []int64
+	where := adapters.Where(repo.Info()).Gt("id", 0)
+	for item, err := range repo.Iter(t.Context(), adapters.QueryOptions{Limit: 10}, where, true) {
+		require.NoError(t, err)
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	require.Len(t, conn.Calls(), 1)
+	assert.Equal(t, "SELECT id, name, tags FROM authors WHERE id>$1 LIMIT 10", conn.Calls()[0].Query)
+
+	for _, err := range repo.Iter(t.Context(), adapters.QueryOptions{WithTotals: true}, nil, false) {
+		require.ErrorIs(t, err, adapters.ErrInvalidOptions)
+	}
+	assert.Len(t, conn.Calls(), 1, "invalid options don't reach the database")
+}
@@ -0,0 +1,241 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultTxAttempts   = 3
+	defaultTxBackoff    = 20 * time.Millisecond
+	defaultTxMaxBackoff = time.Second
+)
+
+// SQLSTATE ошибок, после которых транзакцию можно повторить
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// TxOptions - параметры выполнения транзакции WithTx и WithPGXTx
+type TxOptions struct {
+	Isolation   sql.IsolationLevel // Уровень изоляции, по умолчанию - уровень СУБД
+	ReadOnly    bool               // Транзакция только для чтения
+	MaxAttempts int                // Наибольшее количество попыток выполнения, по умолчанию - 3
+	Backoff     time.Duration      // Пауза перед первым повтором, удваивается с каждым повтором, по умолчанию - 20ms
+	MaxBackoff  time.Duration      // Наибольшая пауза между попытками, по умолчанию - 1s
+
+	// Dialect - диалект, задающий синтаксис точек сохранения вложенных вызовов WithTx (см. Savepointer);
+	// nil - SAVEPOINT, RELEASE SAVEPOINT и ROLLBACK TO SAVEPOINT, как в PostgreSQL, MySQL и SQLite.
+	// Для SQL Server нужно указать MSSQLAdapter. Используется диалект внешнего вызова WithTx
+	Dialect Dialect
+}
+
+// Savepointer - необязательный интерфейс диалекта с собственным синтаксисом точек сохранения
+type Savepointer interface {
+	// SavepointQueries - запросы создания точки сохранения name, её освобождения (пустой - не требуется)
+	// и отката к ней
+	SavepointQueries(name string) (save, release, rollback string)
+}
+
+// savepointQueries - запросы точки сохранения name для диалекта dialect (см. TxOptions.Dialect)
+func savepointQueries(dialect Dialect, name string) (save, release, rollback string) {
+	if savepointer, ok := dialect.(Savepointer); ok {
+		return savepointer.SavepointQueries(name)
+	}
+	return "SAVEPOINT " + name, "RELEASE SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name
+}
+
+// IsRetryable - ошибка pq или pgx означает конфликт сериализации (40001) или взаимоблокировку (40P01),
+// и транзакцию можно выполнить повторно
+func IsRetryable(err error) bool {
+	var (
+		code  string
+		pqErr *pq.Error
+		pgErr *pgconn.PgError
+	)
+	if errors.As(err, &pqErr) {
+		code = string(pqErr.Code)
+	} else if errors.As(err, &pgErr) {
+		code = pgErr.Code
+	}
+	return code == sqlStateSerializationFailure || code == sqlStateDeadlockDetected
+}
+
+type (
+	sqlTxKey struct{}
+	pgxTxKey struct{}
+)
+
+// sqlTxState - транзакция WithTx в контексте, её диалект и глубина вложенности для имён точек сохранения
+type sqlTxState struct {
+	tx      *sql.Tx
+	dialect Dialect
+	depth   int
+}
+
+// TxFromContext - транзакция WithTx, выполняющаяся в ctx
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	state, ok := ctx.Value(sqlTxKey{}).(sqlTxState)
+	return state.tx, ok
+}
+
+// PGXTxFromContext - транзакция WithPGXTx, выполняющаяся в ctx
+func PGXTxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(pgxTxKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// WithTx - выполнить fn в транзакции db и зафиксировать её, если fn не вернула ошибку, иначе - откатить;
+// паника в fn также откатывает транзакцию и передаётся дальше.
+// Транзакция, завершившаяся ошибкой IsRetryable (в fn или при фиксации), повторяется целиком
+// с паузами до opts.MaxAttempts раз. fn получает контекст с транзакцией: вызов WithTx с этим контекстом
+// выполняется в той же транзакции внутри точки сохранения и откатывается до неё при ошибке,
+// без собственных повторов. Поэтому fn может выполняться несколько раз и не должна иметь побочных
+// эффектов вне транзакции. Синтаксис точек сохранения определяется opts.Dialect внешнего вызова
+func WithTx(
+	ctx context.Context, db TxBeginner, opts TxOptions, fn func(ctx context.Context, tx *sql.Tx) error,
+) error {
+	if state, ok := ctx.Value(sqlTxKey{}).(sqlTxState); ok {
+		state.depth++
+		return withSavepoint(context.WithValue(ctx, sqlTxKey{}, state), state, fn)
+	}
+	return retryTx(ctx, opts, func() error {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+		if err != nil {
+			return err
+		}
+		txCtx := context.WithValue(ctx, sqlTxKey{}, sqlTxState{tx: tx, dialect: opts.Dialect})
+		return finishTx(func() error { return fn(txCtx, tx) }, tx.Commit, tx.Rollback)
+	})
+}
+
+// withSavepoint - выполнить вложенный вызов WithTx внутри точки сохранения
+func withSavepoint(ctx context.Context, state sqlTxState, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	tx := state.tx
+	save, release, rollback := savepointQueries(state.dialect, "dbs_sp_"+strconv.Itoa(state.depth))
+	if _, err := tx.ExecContext(ctx, save); err != nil {
+		return err
+	}
+	exec := func(query string) func() error {
+		return func() error {
+			if query == "" {
+				return nil
+			}
+			_, err := tx.ExecContext(ctx, query)
+			return err
+		}
+	}
+	return finishTx(func() error { return fn(ctx, tx) }, exec(release), exec(rollback))
+}
+
+// PGXTxBeginner - общий интерфейс *pgx.Conn и *pgxpool.Pool для открытия транзакций
+type PGXTxBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+var _ PGXTxBeginner = (*pgx.Conn)(nil)
+
+// pgxIsoLevels - уровни изоляции database/sql, поддерживаемые PostgreSQL
+var pgxIsoLevels = map[sql.IsolationLevel]pgx.TxIsoLevel{
+	sql.LevelDefault:         "",
+	sql.LevelReadUncommitted: pgx.ReadUncommitted,
+	sql.LevelReadCommitted:   pgx.ReadCommitted,
+	sql.LevelRepeatableRead:  pgx.RepeatableRead,
+	sql.LevelSerializable:    pgx.Serializable,
+}
+
+var errTxIsolation = errors.New("isolation level isn't supported")
+
+// WithPGXTx - аналог WithTx для pgx: вложенные вызовы с контекстом fn выполняются
+// во вложенной транзакции pgx, то есть внутри точки сохранения
+func WithPGXTx(
+	ctx context.Context, db PGXTxBeginner, opts TxOptions, fn func(ctx context.Context, tx pgx.Tx) error,
+) error {
+	if outer, ok := PGXTxFromContext(ctx); ok {
+		tx, err := outer.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		return runPGXTx(ctx, tx, fn)
+	}
+	isoLevel, ok := pgxIsoLevels[opts.Isolation]
+	if !ok {
+		return errTxIsolation
+	}
+	txOptions := pgx.TxOptions{IsoLevel: isoLevel}
+	if opts.ReadOnly {
+		txOptions.AccessMode = pgx.ReadOnly
+	}
+	return retryTx(ctx, opts, func() error {
+		tx, err := db.BeginTx(ctx, txOptions)
+		if err != nil {
+			return err
+		}
+		return runPGXTx(ctx, tx, fn)
+	})
+}
+
+func runPGXTx(ctx context.Context, tx pgx.Tx, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	commit := func() error { return tx.Commit(ctx) }
+	rollback := func() error { return tx.Rollback(ctx) }
+	txCtx := context.WithValue(ctx, pgxTxKey{}, tx)
+	return finishTx(func() error { return fn(txCtx, tx) }, commit, rollback)
+}
+
+// finishTx - выполнить fn и зафиксировать транзакцию при успехе, иначе - откатить; ошибка отката добавляется
+// к ошибке fn. При панике в fn транзакция откатывается, а паника продолжается
+func finishTx(fn func() error, commit, rollback func() error) error {
+	finished := false
+	defer func() {
+		if !finished {
+			_ = rollback()
+		}
+	}()
+	err := fn()
+	finished = true
+	if err != nil {
+		return errors.Join(err, ignoreTxDone(rollback()))
+	}
+	return commit()
+}
+
+// retryTx - выполнять attempt, пока он завершается ошибкой IsRetryable, не больше opts.MaxAttempts раз
+func retryTx(ctx context.Context, opts TxOptions, attempt func() error) error {
+	attempts, backoff, maxBackoff := opts.MaxAttempts, opts.Backoff, opts.MaxBackoff
+	if attempts <= 0 {
+		attempts = defaultTxAttempts
+	}
+	if backoff <= 0 {
+		backoff = defaultTxBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultTxMaxBackoff
+	}
+
+	var err error
+	for try := 1; ; try++ {
+		if err = attempt(); err == nil || try >= attempts || !IsRetryable(err) {
+			return err
+		}
+		// Случайная добавка к паузе разводит повторы конфликтующих транзакций по времени
+		delay := min(backoff, maxBackoff)
+		delay = delay/2 + rand.N(delay/2+1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
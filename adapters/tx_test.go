@@ -0,0 +1,259 @@
+package adapters_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lib/pq"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ adapters.PGXTxBeginner = (*pgxpool.Pool)(nil)
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, adapters.IsRetryable(&pq.Error{Code: "40001"}))
+	assert.True(t, adapters.IsRetryable(fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"})))
+	assert.False(t, adapters.IsRetryable(&pq.Error{Code: "23505"}))
+	assert.False(t, adapters.IsRetryable(errors.New("40001")))
+	assert.False(t, adapters.IsRetryable(nil))
+}
+
+func TestWithTx_Retry(t *testing.T) {
+	t.Parallel()
+
+	db, conn := openFakeDB(t,
+		fakeResponse{Err: &pq.Error{Code: "40001"}},
+		fakeResponse{Err: &pq.Error{Code: "40P01"}},
+		fakeResponse{RowsAffected: 1},
+	)
+	opts := adapters.TxOptions{Isolation: sql.LevelSerializable, Backoff: time.Millisecond}
+	attempts := 0
+	err := adapters.WithTx(t.Context(), db, opts, func(ctx context.Context, tx *sql.Tx) error {
+		attempts++
+		_, err := tx.ExecContext(ctx, "UPDATE accounts SET amount=amount+1")
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK", "BEGIN", "COMMIT"}, conn.TxLog())
+}
+
+func TestWithTx_NoRetry(t *testing.T) {
+	t.Parallel()
+
+	uniqueErr := &pq.Error{Code: "23505"}
+	db, conn := openFakeDB(t,
+		fakeResponse{Err: uniqueErr},
+		fakeResponse{Err: &pq.Error{Code: "40001"}},
+		fakeResponse{Err: &pq.Error{Code: "40001"}},
+	)
+	exec := func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO accounts DEFAULT VALUES")
+		return err
+	}
+
+	err := adapters.WithTx(t.Context(), db, adapters.TxOptions{}, exec)
+	require.ErrorIs(t, err, uniqueErr)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.TxLog())
+
+	err = adapters.WithTx(t.Context(), db, adapters.TxOptions{MaxAttempts: 2, Backoff: time.Millisecond}, exec)
+	require.True(t, adapters.IsRetryable(err), "last error is returned when attempts are exhausted")
+	assert.Len(t, conn.TxLog(), 6)
+}
+
+func TestWithTx_Savepoints(t *testing.T) {
+	t.Parallel()
+
+	errInner := errors.New("inner failed")
+	db, conn := openFakeDB(t,
+		fakeResponse{}, fakeResponse{}, fakeResponse{}, // SAVEPOINT, DELETE, ROLLBACK TO SAVEPOINT
+		fakeResponse{}, fakeResponse{}, fakeResponse{}, // SAVEPOINT, SAVEPOINT, RELEASE SAVEPOINT
+		fakeResponse{}, // RELEASE SAVEPOINT
+	)
+	repo, err := adapters.NewRepository[TestRec](db, &adapters.PGAdapter{})
+	require.NoError(t, err)
+
+	err = adapters.WithTx(t.Context(), db, adapters.TxOptions{}, func(ctx context.Context, tx *sql.Tx) error {
+		assert.Same(t, tx, repo.FromContext(ctx).DB())
+		err := adapters.WithTx(ctx, db, adapters.TxOptions{}, func(ctx context.Context, _ *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "DELETE FROM test_rec")
+			require.NoError(t, err)
+			return errInner
+		})
+		require.ErrorIs(t, err, errInner)
+
+		return adapters.WithTx(ctx, db, adapters.TxOptions{}, func(ctx context.Context, inner *sql.Tx) error {
+			assert.Same(t, tx, inner)
+			return adapters.WithTx(ctx, db, adapters.TxOptions{}, func(context.Context, *sql.Tx) error {
+				return nil
+			})
+		})
+	})
+	require.NoError(t, err)
+	assert.Same(t, db, repo.FromContext(t.Context()).DB())
+
+	var queries []string
+	for _, call := range conn.Calls() {
+		queries = append(queries, call.Query)
+	}
+	assert.Equal(t, []string{
+		"SAVEPOINT dbs_sp_1", "DELETE FROM test_rec", "ROLLBACK TO SAVEPOINT dbs_sp_1",
+		"SAVEPOINT dbs_sp_1", "SAVEPOINT dbs_sp_2", "RELEASE SAVEPOINT dbs_sp_2", "RELEASE SAVEPOINT dbs_sp_1",
+	}, queries)
+	assert.Equal(t, []string{"BEGIN", "COMMIT"}, conn.TxLog())
+}
+
+func TestWithTx_Panic(t *testing.T) {
+	t.Parallel()
+
+	db, conn := openFakeDB(t, fakeResponse{}, fakeResponse{}) // SAVE TRANSACTION, ROLLBACK TRANSACTION
+	opts := adapters.TxOptions{Dialect: &adapters.MSSQLAdapter{}}
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = adapters.WithTx(t.Context(), db, opts, func(ctx context.Context, _ *sql.Tx) error {
+			return adapters.WithTx(ctx, db, adapters.TxOptions{}, func(context.Context, *sql.Tx) error {
+				panic("boom")
+			})
+		})
+	})
+
+	var queries []string
+	for _, call := range conn.Calls() {
+		queries = append(queries, call.Query)
+	}
+	assert.Equal(t, []string{"SAVE TRANSACTION dbs_sp_1", "ROLLBACK TRANSACTION dbs_sp_1"}, queries)
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.TxLog())
+}
+
+func TestWithTx_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	db, _ := openFakeDB(t, fakeResponse{Err: &pq.Error{Code: "40001"}})
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	err := adapters.WithTx(ctx, db, adapters.TxOptions{Backoff: time.Hour}, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "UPDATE accounts SET amount=0")
+		cancel()
+		return err
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// fakePGXTx - транзакция pgx поверх fakePGX; начало, фиксация и откат записываются в журнал
+type fakePGXTx struct {
+	*fakePGX
+	log   *[]string
+	depth int
+}
+
+func (tx *fakePGXTx) Begin(context.Context) (pgx.Tx, error) {
+	*tx.log = append(*tx.log, "SAVEPOINT")
+	return &fakePGXTx{fakePGX: tx.fakePGX, log: tx.log, depth: tx.depth + 1}, nil
+}
+
+func (tx *fakePGXTx) finish(action string) {
+	if tx.depth > 0 {
+		action += " SAVEPOINT"
+	}
+	*tx.log = append(*tx.log, action)
+}
+
+func (tx *fakePGXTx) Commit(context.Context) error {
+	tx.finish("COMMIT")
+	return nil
+}
+
+func (tx *fakePGXTx) Rollback(context.Context) error {
+	tx.finish("ROLLBACK")
+	return nil
+}
+
+func (tx *fakePGXTx) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, nil
+}
+
+func (tx *fakePGXTx) LargeObjects() pgx.LargeObjects { return pgx.LargeObjects{} }
+
+func (tx *fakePGXTx) Prepare(context.Context, string, string) (*pgconn.StatementDescription, error) {
+	return nil, nil
+}
+
+func (tx *fakePGXTx) Conn() *pgx.Conn { return nil }
+
+// fakePGXBeginner - открывает транзакции fakePGXTx; первые ошибки failures возвращает фиксация
+type fakePGXBeginner struct {
+	db       *fakePGX
+	log      []string
+	failures []error
+}
+
+func (b *fakePGXBeginner) BeginTx(_ context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	b.log = append(b.log, strings.TrimSpace("BEGIN "+string(opts.IsoLevel)))
+	tx := &fakePGXTx{fakePGX: b.db, log: &b.log}
+	if len(b.failures) == 0 {
+		return tx, nil
+	}
+	failure := b.failures[0]
+	b.failures = b.failures[1:]
+	return &failingCommitTx{fakePGXTx: tx, err: failure}, nil
+}
+
+type failingCommitTx struct {
+	*fakePGXTx
+	err error
+}
+
+func (tx *failingCommitTx) Commit(context.Context) error {
+	tx.finish("COMMIT FAILED")
+	return tx.err
+}
+
+func TestWithPGXTx(t *testing.T) {
+	t.Parallel()
+
+	db := &fakePGX{responses: [][][]any{{{int64(11), uint(1), "one", time.Time{}}}}}
+	beginner := &fakePGXBeginner{db: db, failures: []error{&pgconn.PgError{Code: "40001"}}}
+	repo, err := adapters.NewPGXRepository[TestRec](db, &adapters.PGAdapter{})
+	require.NoError(t, err)
+
+	errInner := errors.New("inner failed")
+	opts := adapters.TxOptions{Isolation: sql.LevelSerializable, Backoff: time.Millisecond}
+	attempts := 0
+	err = adapters.WithPGXTx(t.Context(), beginner, opts, func(ctx context.Context, tx pgx.Tx) error {
+		attempts++
+		assert.Equal(t, tx, repo.FromContext(ctx).DB())
+		innerErr := adapters.WithPGXTx(ctx, beginner, opts, func(context.Context, pgx.Tx) error { return errInner })
+		require.ErrorIs(t, innerErr, errInner)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{
+		"BEGIN serializable", "SAVEPOINT", "ROLLBACK SAVEPOINT", "COMMIT FAILED",
+		"BEGIN serializable", "SAVEPOINT", "ROLLBACK SAVEPOINT", "COMMIT",
+	}, beginner.log)
+
+	err = adapters.WithPGXTx(t.Context(), beginner, adapters.TxOptions{Isolation: sql.LevelSnapshot},
+		func(context.Context, pgx.Tx) error { return nil })
+	require.Error(t, err)
+
+	beginner.log = nil
+	assert.Panics(t, func() {
+		_ = adapters.WithPGXTx(t.Context(), beginner, adapters.TxOptions{}, func(context.Context, pgx.Tx) error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, beginner.log)
+}
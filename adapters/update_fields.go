@@ -0,0 +1,63 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mirrorru/dbs"
+)
+
+var (
+	errNoUpdateFields    = errors.New("no fields to update")
+	errPKFieldUpdate     = errors.New("PK field can't be updated by PK")
+	errDuplicateUpdField = errors.New("duplicate field to update")
+)
+
+// updateFieldsList - проверить и найти обновляемые поля: поля должны существовать, не входить в PK и не повторяться
+func updateFieldsList(info *dbs.StructInfo, fieldNames []string) (dbs.FieldInfoList, error) {
+	if len(fieldNames) == 0 {
+		return nil, errNoUpdateFields
+	}
+	setFields, err := peekFields(info, fieldNames)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range setFields {
+		if setFields[idx].IsPK {
+			return nil, fmt.Errorf("%w [%s]", errPKFieldUpdate, setFields[idx].Name)
+		}
+		if slices.Contains(fieldNames[:idx], fieldNames[idx]) {
+			return nil, fmt.Errorf("%w [%s]", errDuplicateUpdField, fieldNames[idx])
+		}
+	}
+	return setFields, nil
+}
+
+// makeUpdateFieldsKey - запросы кэшируются для каждого набора (и порядка) обновляемых полей
+func makeUpdateFieldsKey(info *dbs.StructInfo, fieldNames []string) queryCacheKey {
+	key := makeCacheKey(info, queryKindUpdateFields)
+	key.Variant = variantList(fieldNames)
+	return key
+}
+
+// UpdateFieldsArgs - параметры UpdateFieldsQuery: значения полей fieldNames, затем PK
+func UpdateFieldsArgs[T any](info *dbs.StructInfo, src *T, fieldNames ...string) ([]any, error) {
+	setFields, err := updateFieldsList(info, fieldNames)
+	if err != nil {
+		return nil, err
+	}
+	args, err := setFields.Refs(src)
+	if err != nil {
+		return nil, err
+	}
+	pks, err := info.PKFields().Refs(src)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, pks...), nil
+}
+
+func UpdateFieldsReceivers[T any](info *dbs.StructInfo, dest *T) ([]any, error) {
+	return info.AllFields().Refs(dest)
+}
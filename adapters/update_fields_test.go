@@ -0,0 +1,99 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateFieldsQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		dialect adapters.Dialect
+		need    string
+	}{
+		{
+			dialect: &adapters.PGAdapter{},
+			need:    "UPDATE test_rec SET name=$1, kind=$2 WHERE id=$3 RETURNING id, kind, name, aux_field",
+		},
+		{
+			dialect: &adapters.MySQLAdapter{},
+			need:    "UPDATE test_rec SET name=?, kind=? WHERE id=?",
+		},
+		{
+			dialect: &adapters.MSSQLAdapter{},
+			need: "UPDATE test_rec SET name=@p1, kind=@p2" +
+				" OUTPUT inserted.id, inserted.kind, inserted.name, inserted.aux_field WHERE id=@p3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			t.Parallel()
+			query, errQuery := tt.dialect.UpdateFieldsQuery(si, "name", "kind")
+			require.NoError(t, errQuery)
+			assert.Equal(t, tt.need, query)
+		})
+	}
+}
+
+func TestUpdateFieldsQuery_Cache(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	first, err := pg.UpdateFieldsQuery(si, "name")
+	require.NoError(t, err)
+	second, err := pg.UpdateFieldsQuery(si, "kind")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, 2, pg.QueryCache().Len())
+
+	commaInfo, err := dbs.NewStructInfo(commaRec{})
+	require.NoError(t, err)
+	single, err := pg.UpdateFieldsQuery(commaInfo, "a,b")
+	require.NoError(t, err)
+	pair, err := pg.UpdateFieldsQuery(commaInfo, "a", "b")
+	require.NoError(t, err)
+	assert.NotEqual(t, single, pair, "field lists must not share a cache entry")
+}
+
+func TestUpdateFieldsQuery_Errors(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	_, err = pg.UpdateFieldsQuery(si, "name", "missing")
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+	_, err = pg.UpdateFieldsQuery(si)
+	require.Error(t, err)
+	_, err = pg.UpdateFieldsQuery(si, "id")
+	require.Error(t, err)
+	_, err = pg.UpdateFieldsQuery(si, "name", "name")
+	require.Error(t, err)
+}
+
+func TestUpdateFieldsArgs(t *testing.T) {
+	t.Parallel()
+
+	var rec TestRec
+	si, err := dbs.NewStructInfo(rec)
+	require.NoError(t, err)
+
+	args, err := adapters.UpdateFieldsArgs(si, &rec, "name", "kind")
+	require.NoError(t, err)
+	assert.Equal(t, []any{&rec.Name, &rec.Kind, &rec.ID}, args)
+
+	_, err = adapters.UpdateFieldsArgs(si, &rec, "missing")
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+}
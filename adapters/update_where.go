@@ -0,0 +1,153 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+var (
+	errEmptyCriteria  = errors.New("criteria needed for bulk update or delete, use Or(Where(info)) for all rows")
+	errCriteriaStruct = errors.New("criteria built for another structure")
+	errCriteriaAlias  = errors.New("criteria alias isn't supported in this query")
+	errNoReturning    = errors.New("dialect can't return values from DML")
+)
+
+// whereConditions - условия массового обновления или удаления, параметры нумеруются после prevArgs.
+// Пустые условия считаются ошибкой, чтобы случайно не затронуть всю таблицу
+func whereConditions(
+	dialect Dialect, info *dbs.StructInfo, where *Criteria, prevArgs int, withReturning bool,
+) (string, []any, error) {
+	if withReturning && !dialect.CanReturnValuesInDML() {
+		return "", nil, fmt.Errorf("%w [%s]", errNoReturning, dialect.Name())
+	}
+	if where.IsEmpty() && where.Err() == nil {
+		return "", nil, errEmptyCriteria
+	}
+	return criteriaConditions(dialect, info, where, prevArgs)
+}
+
+// criteriaConditions - условия where для запроса к таблице info без псевдонима,
+// параметры нумеруются после prevArgs. Пустые условия дают пустую строку
+func criteriaConditions(dialect Dialect, info *dbs.StructInfo, where *Criteria, prevArgs int) (string, []any, error) {
+	return aliasedCriteriaConditions(dialect, info, where, "", prevArgs)
+}
+
+// aliasedCriteriaConditions - условия where для запроса к таблице info с псевдонимом alias:
+// поля условий указываются без псевдонима или с псевдонимом alias
+func aliasedCriteriaConditions(
+	dialect Dialect, info *dbs.StructInfo, where *Criteria, alias string, prevArgs int,
+) (string, []any, error) {
+	if err := where.Err(); err != nil {
+		return "", nil, err
+	}
+	switch {
+	case where.IsEmpty():
+		return "", nil, nil
+	case where.info != info:
+		return "", nil, errCriteriaStruct
+	case where.hasAliasOtherThan(alias):
+		return "", nil, fmt.Errorf("%w [%s]", errCriteriaAlias, alias)
+	}
+	return where.Build(dialect, prevArgs)
+}
+
+// prepareUpdateWhere - проверить обновляемые поля и сформировать условия массового обновления
+func prepareUpdateWhere(
+	dialect Dialect, info *dbs.StructInfo, where *Criteria, withReturning bool, fieldNames []string,
+) (dbs.FieldInfoList, string, []any, error) {
+	setFields, err := updateFieldsList(info, fieldNames)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	conds, args, err := whereConditions(dialect, info, where, len(setFields), withReturning)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return setFields, conds, args, nil
+}
+
+// buildUpdateWhere - обновление полей setFields у записей, отобранных условиями conds
+func buildUpdateWhere(
+	dialect Dialect, info *dbs.StructInfo, setFields dbs.FieldInfoList, conds string, withReturning bool,
+) string {
+	var sb strings.Builder
+
+	allFields := info.AllFields()
+	sb.Grow(30 + (len(allFields)+len(setFields))*2*DefaultFieldNameLength + len(conds))
+	_, _ = sb.WriteString("UPDATE ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" SET ")
+	writeEQs(&sb, dialect, setFields, 1, ", ")
+	_, _ = sb.WriteString(" WHERE ")
+	_, _ = sb.WriteString(conds)
+	writeReturning(&sb, dialect, allFields, withReturning)
+
+	return sb.String()
+}
+
+// buildDeleteWhere - удаление записей, отобранных условиями conds
+func buildDeleteWhere(dialect Dialect, info *dbs.StructInfo, conds string, withReturning bool) string {
+	var sb strings.Builder
+
+	sb.Grow(30 + len(info.AllFields())*DefaultFieldNameLength + len(conds))
+	_, _ = sb.WriteString("DELETE FROM ")
+	writeTableName(&sb, dialect, info.TableName())
+	_, _ = sb.WriteString(" WHERE ")
+	_, _ = sb.WriteString(conds)
+	writeReturning(&sb, dialect, info.AllFields(), withReturning)
+
+	return sb.String()
+}
+
+// UpdateWhereArgs - параметры UpdateWhereQuery: значения полей fieldNames записи src, затем параметры условий whereArgs
+func UpdateWhereArgs[T any](info *dbs.StructInfo, src *T, whereArgs []any, fieldNames ...string) ([]any, error) {
+	setFields, err := updateFieldsList(info, fieldNames)
+	if err != nil {
+		return nil, err
+	}
+	args, err := setFields.Refs(src)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, whereArgs...), nil
+}
+
+// UpdateWhere - записать значения полей fieldNames записи src во все записи, отобранные where.
+// Возвращает количество обновлённых записей
+func UpdateWhere[T any](
+	ctx context.Context, db SQLQuerier, dialect Dialect, info *dbs.StructInfo,
+	src *T, where *Criteria, fieldNames ...string,
+) (int64, error) {
+	query, whereArgs, err := dialect.UpdateWhereQuery(info, where, false, fieldNames...)
+	if err != nil {
+		return 0, err
+	}
+	args, err := UpdateWhereArgs(info, src, whereArgs, fieldNames...)
+	if err != nil {
+		return 0, err
+	}
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteWhere - удалить все записи, отобранные where. Возвращает количество удалённых записей
+func DeleteWhere(
+	ctx context.Context, db SQLQuerier, dialect Dialect, info *dbs.StructInfo, where *Criteria,
+) (int64, error) {
+	query, args, err := dialect.DeleteWhereQuery(info, where, false)
+	if err != nil {
+		return 0, err
+	}
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,96 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateWhereQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	where := adapters.Where(si).Eq("kind", 3).IsNull("name")
+	query, args, err := (&adapters.PGAdapter{}).UpdateWhereQuery(si, where, true, "name", "aux_field")
+	require.NoError(t, err)
+	assert.Equal(t,
+		"UPDATE test_rec SET name=$1, aux_field=$2 WHERE kind=$3 AND name IS NULL RETURNING id, kind, name, aux_field",
+		query)
+	assert.Equal(t, []any{3}, args)
+
+	query, _, err = (&adapters.MSSQLAdapter{}).UpdateWhereQuery(si, where, true, "name")
+	require.NoError(t, err)
+	assert.Equal(t,
+		"UPDATE test_rec SET name=@p1 OUTPUT inserted.id, inserted.kind, inserted.name, inserted.aux_field"+
+			" WHERE kind=@p2 AND name IS NULL",
+		query)
+
+	query, _, err = (&adapters.MySQLAdapter{}).UpdateWhereQuery(si, where, false, "name")
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE test_rec SET name=? WHERE kind=? AND name IS NULL", query)
+
+	_, _, err = (&adapters.MySQLAdapter{}).UpdateWhereQuery(si, where, true, "name")
+	require.Error(t, err, "no RETURNING in MySQL")
+	_, _, err = (&adapters.PGAdapter{}).UpdateWhereQuery(si, nil, false, "name")
+	require.Error(t, err, "empty criteria")
+	_, _, err = (&adapters.PGAdapter{}).UpdateWhereQuery(si, where, false, "id")
+	require.Error(t, err, "PK field")
+	_, _, err = (&adapters.PGAdapter{}).UpdateWhereQuery(si, adapters.Where(si).As("t").Eq("id", 1), false, "name")
+	require.Error(t, err, "alias")
+
+	query, _, err = (&adapters.PGAdapter{}).UpdateWhereQuery(si, adapters.Where(si).Or(adapters.Where(si)), false, "name")
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE test_rec SET name=$1 WHERE (1=1)", query)
+}
+
+func TestDeleteWhereQuery(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	where := adapters.Where(si).Lt("id", 10)
+	query, args, err := (&adapters.SQLiteAdapter{}).DeleteWhereQuery(si, where, true)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM test_rec WHERE id<?1 RETURNING id, kind, name, aux_field", query)
+	assert.Equal(t, []any{10}, args)
+
+	query, _, err = (&adapters.MSSQLAdapter{}).DeleteWhereQuery(si, where, false)
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM test_rec WHERE id<@p1", query)
+
+	other, err := dbs.NewStructInfo(PairKeyRec{})
+	require.NoError(t, err)
+	_, _, err = (&adapters.PGAdapter{}).DeleteWhereQuery(other, where, false)
+	require.Error(t, err, "criteria of another structure")
+}
+
+func TestUpdateWhere_DeleteWhere(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	db, conn := openFakeDB(t, fakeResponse{RowsAffected: 4}, fakeResponse{RowsAffected: 2})
+	pg := &adapters.PGAdapter{}
+
+	src := TestRec{TestBody: TestBody{Name: "fixed"}}
+	affected, err := adapters.UpdateWhere(t.Context(), db, pg, si, &src, adapters.Where(si).Eq("kind", 7), "name")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), affected)
+
+	affected, err = adapters.DeleteWhere(t.Context(), db, pg, si, adapters.Where(si).In("kind", []int{1, 2}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	calls := conn.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "UPDATE test_rec SET name=$1 WHERE kind=$2", calls[0].Query)
+	assert.Equal(t, []any{&src.Name, 7}, calls[0].Args)
+	assert.Equal(t, "DELETE FROM test_rec WHERE kind IN ($1, $2)", calls[1].Query)
+}
@@ -0,0 +1,289 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mirrorru/dbs"
+)
+
+// Criteria - условия отбора записей, проверяемые по описанию структуры.
+// Условия объединяются через AND, первая ошибка (неизвестное поле и т.п.) запоминается
+// и возвращается при формировании запроса
+//
+//	where := adapters.Where(info).Eq("kind", 3).In("name", names).IsNull("ptr_id")
+//	query, args, err := adapters.SelectManyWhere(dialect, info, adapters.QueryOptions{Limit: 10}, where)
+type Criteria struct {
+	info  *dbs.StructInfo
+	alias string
+	conds []condition
+	err   error
+}
+
+type condKind byte
+
+const (
+	condCompare condKind = iota
+	condList
+	condNull
+	condGroup
+)
+
+type condition struct {
+	kind   condKind
+	field  string
+	op     string
+	args   []any
+	groups []*Criteria
+}
+
+var (
+	errNotSliceValue = errors.New("slice or array value needed")
+	errNilGroup      = errors.New("nil criteria group")
+	errNilStructInfo = errors.New("nil struct info for criteria")
+)
+
+// Where - начать построение условий отбора для структуры info; nil вместо info - ошибка формирования запроса
+func Where(info *dbs.StructInfo) *Criteria {
+	if info == nil {
+		return &Criteria{err: errNilStructInfo}
+	}
+	return &Criteria{info: info}
+}
+
+// As - указывать поля с псевдонимом таблицы, например, заданным в QueryOptions.WithAlias
+func (c *Criteria) As(alias string) *Criteria {
+	if c.err == nil {
+		if err := validateAlias(alias); err != nil {
+			c.err = err
+		}
+	}
+	c.alias = alias
+	return c
+}
+
+func (c *Criteria) Eq(field string, value any) *Criteria {
+	return c.compare(field, "=", value)
+}
+
+func (c *Criteria) NotEq(field string, value any) *Criteria {
+	return c.compare(field, "<>", value)
+}
+
+func (c *Criteria) Lt(field string, value any) *Criteria {
+	return c.compare(field, "<", value)
+}
+
+func (c *Criteria) Le(field string, value any) *Criteria {
+	return c.compare(field, "<=", value)
+}
+
+func (c *Criteria) Gt(field string, value any) *Criteria {
+	return c.compare(field, ">", value)
+}
+
+func (c *Criteria) Ge(field string, value any) *Criteria {
+	return c.compare(field, ">=", value)
+}
+
+func (c *Criteria) Like(field string, pattern string) *Criteria {
+	return c.compare(field, " LIKE ", pattern)
+}
+
+// In - значение поля входит в список values (слайс или массив). Пустой список не совпадает ни с чем
+func (c *Criteria) In(field string, values any) *Criteria {
+	return c.list(field, " IN ", values)
+}
+
+// NotIn - значение поля не входит в список values (слайс или массив). Пустой список совпадает со всем
+func (c *Criteria) NotIn(field string, values any) *Criteria {
+	return c.list(field, " NOT IN ", values)
+}
+
+func (c *Criteria) IsNull(field string) *Criteria {
+	return c.add(condition{kind: condNull, field: field, op: " IS NULL"})
+}
+
+func (c *Criteria) IsNotNull(field string) *Criteria {
+	return c.add(condition{kind: condNull, field: field, op: " IS NOT NULL"})
+}
+
+// Or - хотя бы одна из групп условий выполняется; условия внутри группы объединяются через AND.
+// Группы без собственного псевдонима (см. As) указывают поля с псевдонимом c. Ошибки групп, в том числе
+// возникшие после вызова Or, возвращаются при формировании запроса; nil вместо группы - ошибка
+func (c *Criteria) Or(groups ...*Criteria) *Criteria {
+	if slices.Contains(groups, nil) && c.err == nil {
+		c.err = errNilGroup
+	}
+	c.conds = append(c.conds, condition{kind: condGroup, groups: slices.DeleteFunc(slices.Clone(groups),
+		func(group *Criteria) bool { return group == nil })})
+	return c
+}
+
+// Err - первая ошибка, возникшая при построении условий, включая условия групп Or
+func (c *Criteria) Err() error {
+	if c == nil {
+		return nil
+	}
+	if c.err != nil {
+		return c.err
+	}
+	for idx := range c.conds {
+		for _, group := range c.conds[idx].groups {
+			if err := group.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasAliasOtherThan - поля условий или их групп указываются с псевдонимом таблицы, отличным от alias
+func (c *Criteria) hasAliasOtherThan(alias string) bool {
+	if c == nil {
+		return false
+	}
+	if c.alias != "" && c.alias != alias {
+		return true
+	}
+	for idx := range c.conds {
+		for _, group := range c.conds[idx].groups {
+			if group.hasAliasOtherThan(alias) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsEmpty - условия не заданы
+func (c *Criteria) IsEmpty() bool {
+	return c == nil || len(c.conds) == 0
+}
+
+func (c *Criteria) compare(field, op string, value any) *Criteria {
+	return c.add(condition{kind: condCompare, field: field, op: op, args: []any{value}})
+}
+
+func (c *Criteria) list(field, op string, values any) *Criteria {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		if c.err == nil {
+			c.err = fmt.Errorf("%w for [%s]", errNotSliceValue, field)
+		}
+		return c
+	}
+	args := make([]any, rv.Len())
+	for idx := range args {
+		args[idx] = rv.Index(idx).Interface()
+	}
+	return c.add(condition{kind: condList, field: field, op: op, args: args})
+}
+
+func (c *Criteria) add(cond condition) *Criteria {
+	switch {
+	case c.err != nil:
+	case c.info == nil:
+		c.err = errNilStructInfo
+	default:
+		if _, found := c.info.PeekField(cond.field); !found {
+			c.err = fmt.Errorf("%w [%s] in [%s]", ErrUnknownField, cond.field, c.info.TableName())
+		}
+	}
+	c.conds = append(c.conds, cond)
+	return c
+}
+
+// Build - сформировать условия (без WHERE) и их параметры.
+// Нумерация параметров продолжается после prevArgs параметров, уже имеющихся в запросе
+func (c *Criteria) Build(dialect Dialect, prevArgs int) (string, []any, error) {
+	if err := c.Err(); err != nil {
+		return "", nil, err
+	}
+	var sb strings.Builder
+	sb.Grow(len(c.conds) * 2 * DefaultFieldNameLength)
+	args := make([]any, 0, len(c.conds))
+	args = c.write(&sb, dialect, c.alias, prevArgs, args)
+	return sb.String(), args, nil
+}
+
+// write - условия c с псевдонимом alias: собственным или унаследованным от внешних условий
+func (c *Criteria) write(sb *strings.Builder, dialect Dialect, alias string, prevArgs int, args []any) []any {
+	for idx := range c.conds {
+		if idx > 0 {
+			_, _ = sb.WriteString(" AND ")
+		}
+		cond := &c.conds[idx]
+		switch cond.kind {
+		case condGroup:
+			args = writeOrGroups(sb, dialect, cond.groups, alias, prevArgs, args)
+			continue
+		case condList:
+			if len(cond.args) == 0 {
+				if cond.op == " IN " {
+					_, _ = sb.WriteString("1=0")
+				} else {
+					_, _ = sb.WriteString("1=1")
+				}
+				continue
+			}
+		}
+		writeCondField(sb, dialect, alias, cond.field)
+		_, _ = sb.WriteString(cond.op)
+		switch cond.kind {
+		case condCompare:
+			args = append(args, cond.args[0])
+			_, _ = sb.WriteString(dialect.Placeholder(prevArgs + len(args)))
+		case condList:
+			_, _ = sb.WriteString("(")
+			for argIdx := range cond.args {
+				if argIdx > 0 {
+					_, _ = sb.WriteString(", ")
+				}
+				args = append(args, cond.args[argIdx])
+				_, _ = sb.WriteString(dialect.Placeholder(prevArgs + len(args)))
+			}
+			_, _ = sb.WriteString(")")
+		}
+	}
+	return args
+}
+
+func writeOrGroups(
+	sb *strings.Builder, dialect Dialect, groups []*Criteria, alias string, prevArgs int, args []any,
+) []any {
+	if len(groups) == 0 {
+		_, _ = sb.WriteString("1=0")
+		return args
+	}
+	_, _ = sb.WriteString("(")
+	for idx, group := range groups {
+		if idx > 0 {
+			_, _ = sb.WriteString(" OR ")
+		}
+		if group.IsEmpty() {
+			_, _ = sb.WriteString("1=1")
+			continue
+		}
+		_, _ = sb.WriteString("(")
+		groupAlias := group.alias
+		if groupAlias == "" {
+			groupAlias = alias
+		}
+		args = group.write(sb, dialect, groupAlias, prevArgs, args)
+		_, _ = sb.WriteString(")")
+	}
+	_, _ = sb.WriteString(")")
+	return args
+}
+
+func writeCondField(sb *strings.Builder, dialect Dialect, alias, field string) {
+	if alias != "" {
+		_, _ = sb.WriteString(dialect.Ident(alias))
+		_, _ = sb.WriteString(".")
+	}
+	_, _ = sb.WriteString(dialect.Ident(field))
+}
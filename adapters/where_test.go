@@ -0,0 +1,72 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/mirrorru/dbs"
+	"github.com/mirrorru/dbs/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhere_Build(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+
+	names := []string{"one", "two"}
+	where, args, err := adapters.Where(si).Eq("kind", 3).In("name", names).IsNull("aux_field").
+		Build(&adapters.PGAdapter{}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "kind=$2 AND name IN ($3, $4) AND aux_field IS NULL", where)
+	assert.Equal(t, []any{3, "one", "two"}, args)
+
+	where, args, err = adapters.Where(si).As("t").Gt("id", 10).
+		Or(adapters.Where(si).Eq("kind", 1), adapters.Where(si).Eq("kind", 2).Like("name", "a%")).
+		Build(&adapters.MSSQLAdapter{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "t.id>@p1 AND ((t.kind=@p2) OR (t.kind=@p3 AND t.name LIKE @p4))", where)
+	assert.Equal(t, []any{10, 1, 2, "a%"}, args)
+
+	where, _, err = adapters.Where(si).As("t").Or(adapters.Where(si).As("s").Eq("kind", 1)).Build(&adapters.PGAdapter{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "((s.kind=$1))", where)
+
+	where, args, err = adapters.Where(si).In("id", []int64{}).NotIn("kind", []int{}).Build(&adapters.MySQLAdapter{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "1=0 AND 1=1", where)
+	assert.Empty(t, args)
+}
+
+func TestWhere_Errors(t *testing.T) {
+	t.Parallel()
+
+	si, err := dbs.NewStructInfo(TestRec{})
+	require.NoError(t, err)
+	pg := &adapters.PGAdapter{}
+
+	_, _, err = adapters.Where(si).Eq("missing", 1).Build(pg, 0)
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+
+	_, _, err = adapters.Where(si).In("kind", 1).Build(pg, 0)
+	require.Error(t, err)
+
+	_, _, err = adapters.Where(si).Or(adapters.Where(si).IsNotNull("missing")).Build(pg, 0)
+	require.ErrorIs(t, err, adapters.ErrUnknownField)
+
+	group := adapters.Where(si)
+	criteria := adapters.Where(si).Or(group)
+	group.Eq("missing", 1)
+	_, _, err = criteria.Build(pg, 0)
+	require.ErrorIs(t, err, adapters.ErrUnknownField, "group errors are reported after Or")
+
+	_, _, err = adapters.Where(si).Or(adapters.Where(si).Eq("kind", 1), nil).Build(pg, 0)
+	require.Error(t, err)
+
+	_, _, err = adapters.Where(nil).Eq("kind", 1).In("name", []string{"a"}).Build(pg, 0)
+	require.Error(t, err)
+
+	_, _, err = adapters.Where(si).As("t; --").Eq("kind", 1).Build(pg, 0)
+	require.ErrorIs(t, err, adapters.ErrInvalidAlias)
+}
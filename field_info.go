@@ -3,6 +3,7 @@ package dbs
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/lib/pq"
@@ -87,6 +88,11 @@ func makeFieldConfig(field reflect.StructField) jointFieldConfig {
 
 type FieldInfoList []FieldInfo
 
+// Index - составной индекс поля в структуре для reflect.Value.FieldByIndex
+func (fi *FieldInfo) Index() []int {
+	return slices.Clone(fi.index)
+}
+
 func (fi *FieldInfo) applyIndex(index []int) {
 	newIndex := make([]int, 0, len(index)+len(fi.index))
 	newIndex = append(newIndex, index...)
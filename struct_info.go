@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/mirrorru/dot"
 )
@@ -13,6 +15,8 @@ var (
 	structInfoMap = dot.SyncStore[reflect.Type, *StructInfo]{}
 
 	errStructBasedTypeNeeded = errors.New("value is not a struct-based")
+
+	ErrInvalidIdentifier = errors.New("invalid identifier")
 )
 
 // TableNamer - интерфейс для получения имени таблицы в БД
@@ -30,6 +34,7 @@ type StructInfo struct {
 	nonPkFields   FieldInfoList
 	nonAutoFields FieldInfoList
 	name2field    map[string]FieldInfo
+	initErr       error // Ошибка первой инициализации, возвращаемая и при повторных обращениях
 }
 
 func NewStructInfo(src any) (*StructInfo, error) {
@@ -49,6 +54,7 @@ var errStructInitFailure = errors.New("struct init failure")
 
 func (s *StructInfo) init(srcType reflect.Type) (err error) {
 	s.onceInit.Do(func() {
+		defer func() { s.initErr = err }()
 		s.structType = srcType
 		structValue := reflect.New(s.structType)
 		if tableNamer, ok := structValue.Interface().(TableNamer); ok {
@@ -56,6 +62,9 @@ func (s *StructInfo) init(srcType reflect.Type) (err error) {
 		} else {
 			s.tableName = dot.ToSnakeCase(s.structType.Name())
 		}
+		if err = checkTableName(s.tableName); err != nil {
+			return
+		}
 
 		if s.allFields, err = getFieldInfo(s.structType); err != nil {
 			return
@@ -68,6 +77,10 @@ func (s *StructInfo) init(srcType reflect.Type) (err error) {
 		s.name2field = make(map[string]FieldInfo, len(s.allFields))
 
 		for _, field := range s.allFields {
+			if err = checkIdentifier(field.Name); err != nil {
+				s.name2field = nil
+				return
+			}
 			if _, ok := s.name2field[field.Name]; ok {
 				panic(fmt.Errorf("duplicate field name [%s]", field.Name))
 			}
@@ -84,10 +97,13 @@ func (s *StructInfo) init(srcType reflect.Type) (err error) {
 			s.name2field[field.Name] = field
 		}
 	})
+	if s.initErr != nil {
+		return s.initErr
+	}
 	if s.name2field == nil {
-		err = errStructInitFailure
+		return errStructInitFailure
 	}
-	return err
+	return nil
 }
 
 func (s *StructInfo) Type() reflect.Type {
@@ -123,6 +139,25 @@ func (s *StructInfo) PeekField(fieldName string) (fieldInfo FieldInfo, found boo
 	return fieldInfo, found
 }
 
+// checkTableName - имя таблицы, возможно, с указанием схемы через точку
+func checkTableName(tableName string) error {
+	for _, part := range strings.Split(tableName, ".") {
+		if err := checkIdentifier(part); err != nil {
+			return fmt.Errorf("table name [%s]: %w", tableName, err)
+		}
+	}
+	return nil
+}
+
+// checkIdentifier - идентификатор должен быть непустым и не содержать управляющих символов;
+// прочие символы допустимы, т.к. при формировании запросов такие имена экранируются
+func checkIdentifier(name string) error {
+	if name == "" || strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return fmt.Errorf("%w [%q]", ErrInvalidIdentifier, name)
+	}
+	return nil
+}
+
 func peekStructInfo(srcType reflect.Type) (*StructInfo, error) {
 	if srcType.Kind() != reflect.Struct {
 		return nil, errStructBasedTypeNeeded
@@ -183,16 +218,17 @@ func getFieldInfo(t reflect.Type) (resultList FieldInfoList, err error) {
 					continue
 				}
 				fld.applyIndex(field.Index)
-				if fieldCfg.isInline || fieldCfg.isReference {
-					fld.applyPrefix(fieldCfg.Name)
-				}
 				if fieldCfg.isReference {
+					// Имя поля в ссылаемой структуре фиксируется до добавления префикса
 					fld.RefData = &fieldReference{
 						StructInfo: info,
 						FieldName:  fld.Name,
 					}
 					fld.IsPK, fld.IsAutogen = fieldCfg.IsPK, fieldCfg.IsAutogen
 				}
+				if fieldCfg.isInline || fieldCfg.isReference {
+					fld.applyPrefix(fieldCfg.Name)
+				}
 
 				resultList = append(resultList, fld)
 			}
@@ -115,3 +115,54 @@ func TestStructInfo_FieldInfoList_Refs(t *testing.T) {
 	}, refs, "AllFields()")
 
 }
+
+func TestStructInfo_RefData(t *testing.T) {
+	t.Parallel()
+	si, err := dbs.NewStructInfo(SomeRec{})
+	require.NoError(t, err)
+
+	for _, name := range []string{"ref_ptr_id", "ref_struct_id"} {
+		fld, found := si.PeekField(name)
+		require.True(t, found, name)
+		require.NotNil(t, fld.RefData, name)
+		assert.Equal(t, "id", fld.RefData.FieldName, name)
+		assert.Equal(t, subRecTableName, fld.RefData.StructInfo.TableName(), name)
+	}
+}
+
+type badTableRec struct {
+	ID int64 `dbs:"pk"`
+}
+
+func (badTableRec) TableName() string {
+	return "public..bad"
+}
+
+type badFieldRec struct {
+	ID int64 `dbs:"pk;name:bad\nname"`
+}
+
+func TestStructInfo_InvalidIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	_, err := dbs.NewStructInfo(badTableRec{})
+	require.ErrorIs(t, err, dbs.ErrInvalidIdentifier)
+
+	_, err = dbs.NewStructInfo(badFieldRec{})
+	require.ErrorIs(t, err, dbs.ErrInvalidIdentifier)
+}
+
+type badRepeatedRec struct {
+	ID int64 `dbs:"pk;name:bad\nname"`
+}
+
+func TestStructInfo_RepeatedInitError(t *testing.T) {
+	t.Parallel()
+
+	_, firstErr := dbs.NewStructInfo(badRepeatedRec{})
+	require.ErrorIs(t, firstErr, dbs.ErrInvalidIdentifier)
+
+	_, secondErr := dbs.NewStructInfo(&badRepeatedRec{})
+	require.ErrorIs(t, secondErr, dbs.ErrInvalidIdentifier)
+	assert.Equal(t, firstErr, secondErr)
+}